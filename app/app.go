@@ -0,0 +1,224 @@
+// Package app holds the toggle, set and status logic behind llt-helper's
+// commands, so a Go program (e.g. a Stream Dock plugin) can switch power
+// modes without running llt-helper.exe. The CLI itself calls these functions;
+// it only adds flag parsing, console output and its state file.
+//
+// The core types live in internal packages, which other modules can't
+// import, so they're re-exported here as aliases:
+//
+//	client, err := app.NewClient()
+//	if err != nil {
+//		return err
+//	}
+//	mode, err := app.Toggle(client, app.NewManager(), app.NopNotifier{}, app.Options{})
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// Core types, see the llt, modes and toast packages
+type (
+	Client        = llt.Client
+	CommandRunner = llt.CommandRunner
+	Manager       = modes.Manager
+	PowerMode     = modes.PowerMode
+	ModeMetadata  = modes.ModeMetadata
+	Notifier      = toast.Notifier
+	NopNotifier   = toast.NopNotifier
+)
+
+// Constructors for the core types
+var (
+	// NewClient finds llt.exe through LLT_PATH or its default install locations
+	NewClient = llt.NewClient
+	// NewClientAt uses the llt.exe at a given path
+	NewClientAt = llt.NewClientAt
+	// NewClientWithRunner sends llt.exe invocations to a CommandRunner, e.g. a fake in tests
+	NewClientWithRunner = llt.NewClientWithRunner
+	// NewManager returns a manager with the default quiet/balance/performance cycle
+	NewManager = modes.NewManager
+	// NewNotifier returns the on-screen display used by the CLI (Windows only)
+	NewNotifier = toast.NewNotifier
+)
+
+// Errors returned by the Client, for errors.Is
+var (
+	ErrFeatureUnsupported  = llt.ErrFeatureUnsupported
+	ErrBusy                = llt.ErrBusy
+	ErrLLTBusyUpdating     = llt.ErrLLTBusyUpdating
+	ErrLLTNotFound         = llt.ErrLLTNotFound
+	ErrLLTNotExecutable    = llt.ErrLLTNotExecutable
+	ErrElevationRequired   = llt.ErrElevationRequired
+	ErrUnsupportedPlatform = llt.ErrUnsupportedPlatform
+)
+
+// ErrUnknownMode reports a power mode the manager has no metadata for
+var ErrUnknownMode = errors.New("unknown power mode")
+
+// ErrAlreadyActive is returned by Set when it leaves a mode that is already
+// active alone; most callers treat it as success
+var ErrAlreadyActive = errors.New("mode already active")
+
+// Options tunes Toggle and Set. The zero value cycles the manager's
+// sequence, skips modes that are already active and reports nothing.
+type Options struct {
+	// Modes limits Toggle's cycle; empty means the manager's sequence
+	Modes []PowerMode
+
+	// Reverse makes Toggle move to the previous mode instead of the next
+	Reverse bool
+
+	// Force makes Set apply a mode even when it's already active
+	Force bool
+
+	// Confirm, when set, is asked before switching to a mode; an error
+	// cancels the switch and is returned
+	Confirm func(mode string) error
+
+	// Changed, when set, is called once a mode was set and verified
+	Changed func(mode string)
+
+	// Message returns the toast text for switching to a mode, name being
+	// the display name (with Toggle's cycle position). Unset, it's
+	// toast.ModeChangeMessage.
+	Message func(meta ModeMetadata, name string) string
+
+	// Warn, when set, receives problems that don't fail the call, such as
+	// a toast that couldn't be shown
+	Warn func(err error)
+}
+
+func (o Options) confirm(mode string) error {
+	if o.Confirm == nil {
+		return nil
+	}
+	return o.Confirm(mode)
+}
+
+func (o Options) changed(mode string) {
+	if o.Changed != nil {
+		o.Changed(mode)
+	}
+}
+
+func (o Options) message(meta ModeMetadata, name string) string {
+	if o.Message == nil {
+		return toast.ModeChangeMessage(name)
+	}
+	return o.Message(meta, name)
+}
+
+func (o Options) warn(err error) {
+	if o.Warn != nil {
+		o.Warn(err)
+	}
+}
+
+// Toggle moves to the mode after the current one in opts.Modes (the
+// manager's sequence when empty), or before it with opts.Reverse, and shows
+// its toast, returning that mode.
+func Toggle(client *Client, manager *Manager, notifier Notifier, opts Options) (PowerMode, error) {
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		return "", err
+	}
+
+	next := manager.GetNextModeFromList(PowerMode(current), opts.Modes)
+	if opts.Reverse {
+		next = manager.GetPrevModeFromList(PowerMode(current), opts.Modes)
+	}
+
+	// The default cycle is the modes LLT lists. A current mode missing from
+	// that list while in the configured cycle means LLT disagrees with
+	// itself (version skew), so the configured cycle is used instead (see
+	// Manager.CycleFor). A mode name the helper doesn't know at all usually
+	// means LLT and the helper disagree on mode names (a newer or older
+	// LLT). Either way the cycle stays predictable; just say so.
+	cycle := manager.CycleFor(PowerMode(current), opts.Modes)
+	switch {
+	case len(opts.Modes) == 0 && !slices.Contains(manager.Cycle(nil), PowerMode(current)) && slices.Contains(cycle, PowerMode(current)):
+		opts.warn(fmt.Errorf("LLT reports mode '%s' but doesn't list it as available; cycling the configured sequence to %s", current, next))
+	case !manager.IsValidMode(current):
+		opts.warn(fmt.Errorf("LLT reports unknown mode '%s' (version mismatch? see --mode-map); moving to %s", current, next))
+	}
+
+	if err := opts.confirm(string(next)); err != nil {
+		return "", err
+	}
+
+	if err := SetVerified(client, manager, string(next)); err != nil {
+		return "", err
+	}
+	opts.changed(string(next))
+
+	// Show where the landing mode sits in the cycle, e.g. "Balance (2/3)".
+	// next always comes from the cycle, even when current wasn't in it.
+	meta := manager.GetModeMetadata(next)
+	name := meta.Name
+	if pos := slices.Index(cycle, next); pos >= 0 {
+		name = fmt.Sprintf("%s (%d/%d)", meta.Name, pos+1, len(cycle))
+	}
+	if err := notifier.ShowModeChange(opts.message(meta, name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+		// Don't fail, as the mode was set successfully
+		opts.warn(fmt.Errorf("toast notification failed: %w", err))
+	}
+
+	return next, nil
+}
+
+// Set switches to mode and shows its toast. Unless opts.Force is set, a
+// mode that is already active is left alone, without a toast, returning
+// ErrAlreadyActive, so re-asserting it doesn't spawn llt.exe to set it again.
+func Set(client *Client, manager *Manager, notifier Notifier, mode string, opts Options) error {
+	if !manager.IsValidMode(mode) {
+		return fmt.Errorf("%w: %s", ErrUnknownMode, mode)
+	}
+	if !opts.Force {
+		// If the mode can't be read, set it anyway
+		if current, err := client.GetCurrentMode(); err == nil && current == mode {
+			return ErrAlreadyActive
+		}
+	}
+	if err := opts.confirm(mode); err != nil {
+		return err
+	}
+
+	if err := SetVerified(client, manager, mode); err != nil {
+		return err
+	}
+	opts.changed(mode)
+
+	meta := manager.GetModeMetadata(PowerMode(mode))
+	if err := notifier.ShowModeChange(opts.message(meta, meta.Name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+		opts.warn(fmt.Errorf("toast notification failed: %w", err))
+	}
+
+	return nil
+}
+
+// SetVerified sets the power mode and, for modes outside the built-in list,
+// re-reads it to confirm the change happened. Some LLT builds exit 0 for
+// mode names they don't support, which would otherwise look like success.
+func SetVerified(client *Client, manager *Manager, mode string) error {
+	if err := client.SetMode(mode); err != nil {
+		return err
+	}
+
+	if !manager.IsBuiltinMode(mode) {
+		current, err := client.GetCurrentMode()
+		if err != nil {
+			return fmt.Errorf("could not verify mode change: %w", err)
+		}
+		if current != mode {
+			return fmt.Errorf("LLT accepted mode '%s' but the power mode is still '%s'", mode, current)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeLLT answers llt.exe invocations for a device whose power mode is
+// current, listing available for `f set power-mode -l`
+type fakeLLT struct {
+	t         *testing.T
+	current   string
+	available string
+	sets      []string
+
+	// ignored is a mode LLT accepts with exit code 0 without switching to it
+	ignored string
+}
+
+func (f *fakeLLT) Run(ctx context.Context, args ...string) ([]byte, error) {
+	switch command := strings.Join(args, " "); {
+	case command == "f get power-mode":
+		return []byte(f.current + "\r\n"), nil
+	case command == "f set power-mode -l":
+		return []byte(f.available), nil
+	case strings.HasPrefix(command, "f set power-mode "):
+		mode := args[len(args)-1]
+		f.sets = append(f.sets, mode)
+		if mode != f.ignored {
+			f.current = mode
+		}
+		return nil, nil
+	}
+	f.t.Errorf("unexpected llt.exe call: %q", args)
+	return nil, errors.New("unexpected call")
+}
+
+func newFakeClient(t *testing.T, current, available string) (*Client, *fakeLLT) {
+	t.Helper()
+	fake := &fakeLLT{t: t, current: current, available: available}
+	client := NewClientWithRunner(`C:\LLT\llt.exe`, fake)
+	client.Retries = -1
+	return client, fake
+}
+
+// managerFor builds the cycle from the modes LLT lists, as llt-helper does
+func managerFor(t *testing.T, client *Client) *Manager {
+	t.Helper()
+	available, err := client.ListAvailableModes()
+	if err != nil {
+		t.Fatalf("ListAvailableModes: %v", err)
+	}
+	manager := NewManager()
+	manager.SetSequence(available)
+	return manager
+}
+
+func TestToggleCurrentNotAvailable(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		available string
+		want      PowerMode
+		warned    bool
+	}{
+		{"current missing from the list", "balance", "quiet\nperformance\n", "performance", true},
+		{"current missing from the list, last in the configured cycle", "performance", "quiet\nbalance\n", "quiet", true},
+		{"unknown current mode", "extreme", "quiet\nbalance\nperformance\n", "quiet", true},
+		{"empty list keeps the default cycle", "performance", "", "quiet", false},
+		{"empty list, unknown current mode", "extreme", "", "quiet", true},
+		{"godmode outside the cycle", "godmode", "quiet\nbalance\nperformance\ngodmode\n", "quiet", false},
+		{"lists agree", "quiet", "quiet\nperformance\n", "performance", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newFakeClient(t, tt.current, tt.available)
+			manager := managerFor(t, client)
+
+			var warnings []error
+			mode, err := Toggle(client, manager, NopNotifier{}, Options{
+				Warn: func(err error) { warnings = append(warnings, err) },
+			})
+			if err != nil {
+				t.Fatalf("Toggle: %v", err)
+			}
+			if mode != tt.want || fake.current != string(tt.want) {
+				t.Errorf("Toggle = %q (LLT in %q), want %q", mode, fake.current, tt.want)
+			}
+			if warned := len(warnings) > 0; warned != tt.warned {
+				t.Errorf("warnings = %v, want a warning: %v", warnings, tt.warned)
+			}
+		})
+	}
+}
+
+func TestToggleCurrentNotAvailableReverse(t *testing.T) {
+	client, _ := newFakeClient(t, "balance", "quiet\nperformance\n")
+	manager := managerFor(t, client)
+	notifier := &recordingNotifier{}
+
+	var warnings []error
+	mode, err := Toggle(client, manager, notifier, Options{
+		Reverse: true,
+		Warn:    func(err error) { warnings = append(warnings, err) },
+	})
+	if err != nil {
+		t.Fatalf("Toggle: %v", err)
+	}
+	if mode != "quiet" {
+		t.Errorf("Toggle = %q, want quiet from the configured cycle", mode)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "configured sequence") {
+		t.Errorf("warnings = %v, want one about the configured sequence", warnings)
+	}
+	// The position is within the cycle actually used
+	if len(notifier.changes) != 1 || notifier.changes[0] != "Switched to Quiet (1/3) Mode" {
+		t.Errorf("toasts = %q, want the position in the configured cycle", notifier.changes)
+	}
+}
+
+func TestToggleUnknownModeFallback(t *testing.T) {
+	client, _ := newFakeClient(t, "extreme", "quiet\nperformance\n")
+	manager := managerFor(t, client)
+	manager.SetUnknownFallback("performance")
+
+	mode, err := Toggle(client, manager, NopNotifier{}, Options{})
+	if err != nil {
+		t.Fatalf("Toggle: %v", err)
+	}
+	if mode != "performance" {
+		t.Errorf("Toggle = %q, want the configured fallback performance", mode)
+	}
+}
+
+// recordingNotifier records the mode change and error toasts it's asked
+// to show
+type recordingNotifier struct {
+	NopNotifier
+	changes []string
+	errors  []string
+	err     error
+}
+
+func (n *recordingNotifier) ShowModeChange(message, iconPath, color, position string) error {
+	n.changes = append(n.changes, message)
+	return n.err
+}
+
+func (n *recordingNotifier) ShowError(message string) error {
+	n.errors = append(n.errors, message)
+	return n.err
+}
+
+func TestToggleNotifies(t *testing.T) {
+	client, fake := newFakeClient(t, "quiet", "")
+	notifier := &recordingNotifier{}
+
+	var changed []string
+	mode, err := Toggle(client, NewManager(), notifier, Options{
+		Changed: func(mode string) { changed = append(changed, mode) },
+	})
+	if err != nil {
+		t.Fatalf("Toggle: %v", err)
+	}
+	if mode != "balance" || len(fake.sets) != 1 {
+		t.Errorf("Toggle = %q after setting %q, want balance set once", mode, fake.sets)
+	}
+	if len(changed) != 1 || changed[0] != "balance" {
+		t.Errorf("Changed called with %q, want [balance]", changed)
+	}
+	if want := []string{"Switched to Balance (2/3) Mode"}; len(notifier.changes) != 1 || notifier.changes[0] != want[0] {
+		t.Errorf("toasts = %q, want %q", notifier.changes, want)
+	}
+}
+
+func TestToggleReverse(t *testing.T) {
+	client, _ := newFakeClient(t, "quiet", "")
+
+	mode, err := Toggle(client, NewManager(), NopNotifier{}, Options{Reverse: true, Modes: []PowerMode{"quiet", "godmode"}})
+	if err != nil {
+		t.Fatalf("Toggle: %v", err)
+	}
+	if mode != "godmode" {
+		t.Errorf("Toggle = %q, want godmode", mode)
+	}
+}
+
+func TestToggleConfirmCancels(t *testing.T) {
+	client, fake := newFakeClient(t, "quiet", "")
+	notifier := &recordingNotifier{}
+	cancelled := errors.New("cancelled")
+
+	_, err := Toggle(client, NewManager(), notifier, Options{Confirm: func(string) error { return cancelled }})
+	if !errors.Is(err, cancelled) {
+		t.Errorf("Toggle error = %v, want the Confirm error", err)
+	}
+	if len(fake.sets) != 0 || len(notifier.changes) != 0 {
+		t.Errorf("cancelled toggle set %q and showed %q", fake.sets, notifier.changes)
+	}
+}
+
+func TestToggleToastFailureWarns(t *testing.T) {
+	client, _ := newFakeClient(t, "quiet", "")
+	notifier := &recordingNotifier{err: errors.New("no desktop")}
+
+	var warnings []error
+	if _, err := Toggle(client, NewManager(), notifier, Options{Warn: func(err error) { warnings = append(warnings, err) }}); err != nil {
+		t.Fatalf("Toggle failed because of the toast: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "no desktop") {
+		t.Errorf("warnings = %v, want the toast failure", warnings)
+	}
+}
+
+func TestSet(t *testing.T) {
+	client, fake := newFakeClient(t, "quiet", "")
+	notifier := &recordingNotifier{}
+
+	if err := Set(client, NewManager(), notifier, "performance", Options{}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if fake.current != "performance" {
+		t.Errorf("LLT in %q, want performance", fake.current)
+	}
+	if len(notifier.changes) != 1 || notifier.changes[0] != "Switched to Performance Mode" {
+		t.Errorf("toasts = %q, want one for performance", notifier.changes)
+	}
+}
+
+func TestSetAlreadyActive(t *testing.T) {
+	client, fake := newFakeClient(t, "balance", "")
+	notifier := &recordingNotifier{}
+
+	if err := Set(client, NewManager(), notifier, "balance", Options{}); !errors.Is(err, ErrAlreadyActive) {
+		t.Errorf("Set error = %v, want ErrAlreadyActive", err)
+	}
+	if len(fake.sets) != 0 || len(notifier.changes) != 0 {
+		t.Errorf("Set of the active mode set %q and showed %q", fake.sets, notifier.changes)
+	}
+
+	if err := Set(client, NewManager(), notifier, "balance", Options{Force: true}); err != nil {
+		t.Fatalf("Set with Force: %v", err)
+	}
+	if len(fake.sets) != 1 || len(notifier.changes) != 1 {
+		t.Errorf("forced Set set %q and showed %q, want one of each", fake.sets, notifier.changes)
+	}
+}
+
+func TestSetUnknownMode(t *testing.T) {
+	client, fake := newFakeClient(t, "balance", "")
+
+	if err := Set(client, NewManager(), NopNotifier{}, "turbo", Options{}); !errors.Is(err, ErrUnknownMode) {
+		t.Errorf("Set error = %v, want ErrUnknownMode", err)
+	}
+	if len(fake.sets) != 0 {
+		t.Errorf("Set of an unknown mode ran llt.exe: %q", fake.sets)
+	}
+}
+
+func TestSetVerifiedSilentNoop(t *testing.T) {
+	client, fake := newFakeClient(t, "balance", "")
+	manager := NewManager()
+	manager.SetCustomMetadata(map[PowerMode]ModeMetadata{"turbo": {}})
+	fake.ignored = "turbo"
+
+	err := Set(client, manager, NopNotifier{}, "turbo", Options{})
+	if err == nil || !strings.Contains(err.Error(), "still 'balance'") {
+		t.Errorf("Set error = %v, want the silent no-op reported", err)
+	}
+	if len(fake.sets) != 1 {
+		t.Errorf("got sets %q, want one attempt", fake.sets)
+	}
+}
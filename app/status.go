@@ -0,0 +1,74 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// Status describes a power mode; it's the status command's JSON output
+type Status struct {
+	Mode     string `json:"mode"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Color    string `json:"color"`
+	IconPath string `json:"iconPath"`
+	Icon     string `json:"icon"`            // IconPath made absolute, or "" if the file doesn't exist
+	Index    int    `json:"index,omitempty"` // LLT's numeric mode index
+
+	// Timestamp is when the mode was read (ISO 8601), with --with-timestamp
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// Automation lists LLT automation pipelines that may change the mode on
+	// their own (status --json and --verbose only)
+	Automation []string `json:"automation,omitempty"`
+
+	// Battery is the charge level in percent (status --json and --verbose
+	// only), left out when there's no battery
+	Battery *int `json:"battery,omitempty"`
+
+	// PowerSource is "ac" or "battery" (status --json and --verbose only),
+	// left out when Windows can't tell
+	PowerSource string `json:"powerSource,omitempty"`
+}
+
+// CurrentStatus reads the current mode and describes it
+func CurrentStatus(client *Client, manager *Manager) (Status, error) {
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		return Status{}, err
+	}
+
+	return Describe(manager, current), nil
+}
+
+// Describe builds the Status for a mode, without reading anything from LLT
+func Describe(manager *Manager, mode string) Status {
+	meta := manager.GetModeMetadata(PowerMode(mode))
+	return Status{
+		Mode:     mode,
+		Name:     meta.Name,
+		Symbol:   meta.Symbol,
+		Color:    meta.Color,
+		IconPath: meta.IconPath,
+		Icon:     existingIcon(meta.IconPath),
+		Index:    llt.IndexForMode(mode),
+	}
+}
+
+// existingIcon returns the absolute path of icon if the file exists, so a
+// plugin can load it directly, and "" otherwise
+func existingIcon(icon string) string {
+	if icon == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(icon)
+	if err != nil {
+		return ""
+	}
+	if info, err := os.Stat(abs); err != nil || info.IsDir() {
+		return ""
+	}
+	return abs
+}
@@ -0,0 +1,72 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+)
+
+// builtinAliases are short forms for the most common set commands, and
+// prev for toggling backwards
+var builtinAliases = map[string][]string{
+	"perf": {"set", "--mode=performance"},
+	"q":    {"set", "--mode=quiet"},
+	"bal":  {"set", "--mode=balance"},
+	"prev": {"toggle", "--reverse"},
+}
+
+// resolveAlias expands a leading alias in args, repeatedly so aliases may
+// refer to other aliases. User-defined aliases take precedence over the
+// built-in ones. An alias whose expansion starts with its own name, such as
+// "toggle": ["toggle", "--no-toast"], adds flags to the command it shadows
+// and is expanded once; any other way back to an alias is reported as a loop.
+func resolveAlias(args []string, userAliases map[string][]string) ([]string, error) {
+	seen := make(map[string]bool)
+
+	for len(args) > 0 {
+		expansion, ok := userAliases[args[0]]
+		if !ok {
+			expansion, ok = builtinAliases[args[0]]
+		}
+		if !ok {
+			return args, nil
+		}
+
+		if seen[args[0]] {
+			return nil, fmt.Errorf("alias loop detected at '%s'", args[0])
+		}
+		seen[args[0]] = true
+
+		if len(expansion) == 0 {
+			return nil, fmt.Errorf("alias '%s' is empty", args[0])
+		}
+		name := args[0]
+		args = append(append([]string{}, expansion...), args[1:]...)
+		if expansion[0] == name {
+			return args, nil
+		}
+	}
+
+	return args, nil
+}
+
+// defaultCommandEnv names the environment variable holding the command to
+// run when no arguments are given
+const defaultCommandEnv = "LLT_HELPER_DEFAULT_COMMAND"
+
+// defaultCommand returns the arguments to run when none are given, and where
+// they came from: LLT_HELPER_DEFAULT_COMMAND first, then "defaultCommand" in
+// the config file. It returns nil when neither is set.
+func defaultCommand(cfg *config.Config) ([]string, string) {
+	if args := strings.Fields(os.Getenv(defaultCommandEnv)); len(args) > 0 {
+		return args, sourceEnv
+	}
+	if len(cfg.DefaultCommand) > 0 {
+		return cfg.DefaultCommand, sourceFile
+	}
+	return nil, sourceDefault
+}
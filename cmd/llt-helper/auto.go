@@ -0,0 +1,53 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// autoOptions holds the flags of the auto command
+type autoOptions struct {
+	onAC      string
+	onBattery string
+	apply     bool
+}
+
+// handleAuto picks the mode for the current power source (--on-ac or
+// --on-battery), reading it once. Without apply it only says which mode
+// that is; with apply the mode is set like `set` does: left alone when
+// already active, with the mode's OSD otherwise.
+func handleAuto(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, opts autoOptions, confirmOpts confirmOptions, force bool) error {
+	if opts.onAC == "" && opts.onBattery == "" {
+		return fmt.Errorf("auto needs --on-ac and/or --on-battery")
+	}
+	for _, flag := range []struct{ name, mode string }{{"on-ac", opts.onAC}, {"on-battery", opts.onBattery}} {
+		if flag.mode != "" && !manager.IsValidMode(flag.mode) {
+			return fmt.Errorf("%w: --%s=%s", errUnknownMode, flag.name, flag.mode)
+		}
+	}
+
+	source, err := powerSource()
+	if err != nil {
+		return fmt.Errorf("could not determine the power source: %w", err)
+	}
+
+	mode := opts.onBattery
+	if source == onlyOnAC {
+		mode = opts.onAC
+	}
+	if mode == "" {
+		printOut(fmt.Sprintf("On %s: no mode given for it, nothing to do\n", powerSourceName(source)))
+		return nil
+	}
+
+	if !opts.apply {
+		printOut(fmt.Sprintf("On %s: %s (pass --apply to set it)\n", powerSourceName(source), mode))
+		return nil
+	}
+	return handleSet(client, manager, mode, notifier, confirmOpts, force)
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handleBacklight prints the keyboard backlight level or, with toggle,
+// advances it to the next level the device supports (e.g. Off → Low → High
+// → Off), wrapping around like the power mode cycle
+func handleBacklight(client *llt.Client, notifier toast.Notifier, toggle bool) error {
+	current, err := client.GetKeyboardBacklight()
+	if err != nil {
+		return err
+	}
+
+	if !toggle {
+		printOut(fmt.Sprintf("Keyboard backlight: %s\n", current))
+		return nil
+	}
+
+	levels, err := client.KeyboardBacklightLevels()
+	if err != nil {
+		return err
+	}
+
+	next := nextValue(levels, current)
+	if err := client.SetKeyboardBacklight(next); err != nil {
+		return err
+	}
+	printOut(fmt.Sprintf("Keyboard backlight: %s\n", next))
+
+	if err := notifier.Show("Keyboard Backlight", next); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// benchmarkOptions holds the flags understood by the (hidden) benchmark command
+type benchmarkOptions struct {
+	count  int
+	mode   string
+	dryRun bool
+	json   bool
+}
+
+// latencyStats summarizes a set of timings in milliseconds
+type latencyStats struct {
+	MinMs float64 `json:"minMs"`
+	AvgMs float64 `json:"avgMs"`
+	MaxMs float64 `json:"maxMs"`
+}
+
+// benchmarkResult is the benchmark command's output
+type benchmarkResult struct {
+	Count  int          `json:"count"`
+	Mode   string       `json:"mode"`
+	DryRun bool         `json:"dryRun"`
+	Get    latencyStats `json:"get"`
+	Set    latencyStats `json:"set,omitzero"`
+	Cycle  latencyStats `json:"cycle"`
+	Spawn  latencyStats `json:"spawn"`
+}
+
+// handleBenchmark runs count get/set cycles and reports their latency, plus
+// the bare cost of spawning a process for comparison. Setting the mode that
+// is already active (the default) avoids any visible side effect; --dry-run
+// skips the set entirely.
+func handleBenchmark(client *llt.Client, opts benchmarkOptions) error {
+	if opts.count <= 0 {
+		return fmt.Errorf("--count must be positive")
+	}
+
+	mode := opts.mode
+	if mode == "" {
+		current, err := client.GetCurrentMode()
+		if err != nil {
+			return err
+		}
+		mode = current
+	}
+
+	var gets, sets, cycles, spawns []time.Duration
+	for i := 0; i < opts.count; i++ {
+		start := time.Now()
+		if _, err := client.GetCurrentMode(); err != nil {
+			return err
+		}
+		gets = append(gets, time.Since(start))
+
+		if !opts.dryRun {
+			setStart := time.Now()
+			if err := client.SetMode(mode); err != nil {
+				return err
+			}
+			sets = append(sets, time.Since(setStart))
+		}
+		cycles = append(cycles, time.Since(start))
+
+		spawn, err := spawnOverhead()
+		if err != nil {
+			return err
+		}
+		spawns = append(spawns, spawn)
+	}
+
+	result := benchmarkResult{
+		Count:  opts.count,
+		Mode:   mode,
+		DryRun: opts.dryRun,
+		Get:    summarize(gets),
+		Set:    summarize(sets),
+		Cycle:  summarize(cycles),
+		Spawn:  summarize(spawns),
+	}
+
+	if opts.json {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode benchmark: %w", err)
+		}
+		printOut(string(data) + "\n")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d cycles against %s", result.Count, result.Mode)
+	if result.DryRun {
+		fmt.Fprintf(&b, " (dry run, no set)")
+	}
+	fmt.Fprintf(&b, "\n%-8s %10s %10s %10s\n", "", "min", "avg", "max")
+	rows := []struct {
+		name  string
+		stats latencyStats
+	}{{"get", result.Get}, {"set", result.Set}, {"cycle", result.Cycle}, {"spawn", result.Spawn}}
+	for _, row := range rows {
+		if row.name == "set" && result.DryRun {
+			continue
+		}
+		fmt.Fprintf(&b, "%-8s %8.1fms %8.1fms %8.1fms\n", row.name, row.stats.MinMs, row.stats.AvgMs, row.stats.MaxMs)
+	}
+	printOut(b.String())
+	return nil
+}
+
+// spawnOverhead times starting and waiting for a trivial hidden process,
+// the fixed cost every llt.exe call pays before LLT does any work
+func spawnOverhead() (time.Duration, error) {
+	cmd := exec.Command("cmd.exe", "/c", "exit", "0")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("failed to measure spawn overhead: %w", err)
+	}
+	return time.Since(start), nil
+}
+
+// summarize computes min/avg/max of the timings
+func summarize(timings []time.Duration) latencyStats {
+	if len(timings) == 0 {
+		return latencyStats{}
+	}
+
+	lo, hi, total := timings[0], timings[0], time.Duration(0)
+	for _, t := range timings {
+		lo = min(lo, t)
+		hi = max(hi, t)
+		total += t
+	}
+
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	return latencyStats{MinMs: ms(lo), AvgMs: ms(total / time.Duration(len(timings))), MaxMs: ms(hi)}
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/broadcast"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// broadcastChanges is set by --broadcast
+var broadcastChanges bool
+
+// announceModeChange tells listening programs the helper changed the mode,
+// if --broadcast is on. Failing to do so doesn't fail the command.
+func announceModeChange(mode string) {
+	if !broadcastChanges {
+		return
+	}
+	if err := broadcast.ModeChanged(llt.IndexForMode(mode)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,155 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handleConfigExport writes the config file's settings to path for
+// `config import` on another machine. Only settings are exported: the lock,
+// history and other device state stay behind.
+func handleConfigExport(cfg *config.Config, path string) error {
+	if err := cfg.Export(path); err != nil {
+		return err
+	}
+	printOut(fmt.Sprintf("Exported configuration to %s\n", path))
+	return nil
+}
+
+// handleConfigImport replaces the config file with an export, after
+// dropping entries that don't validate. The previous config is kept next
+// to it as a .bak file. Everything that couldn't be applied is listed.
+// The import is written to a temporary file first and only then swapped
+// in, so a failed write never leaves the user without a config.
+func handleConfigImport(configPath, path string) error {
+	cfg, skipped, err := config.Import(path)
+	if err != nil {
+		return err
+	}
+
+	var issues []string
+	for _, name := range skipped {
+		issues = append(issues, fmt.Sprintf("%s: unknown setting, skipped", name))
+	}
+	issues = append(issues, validateImport(cfg)...)
+
+	tmpPath := configPath + ".import"
+	if err := cfg.Save(tmpPath); err != nil {
+		return err
+	}
+
+	backupPath := configPath + ".bak"
+	backedUp := false
+	if _, err := os.Stat(configPath); err == nil {
+		if err := os.Rename(configPath, backupPath); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to back up %s: %w", configPath, err)
+		}
+		backedUp = true
+	} else if !errors.Is(err, os.ErrNotExist) {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to check %s: %w", configPath, err)
+	}
+
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		os.Remove(tmpPath)
+		if !backedUp {
+			return fmt.Errorf("failed to write %s: %w", configPath, err)
+		}
+		if restoreErr := os.Rename(backupPath, configPath); restoreErr != nil {
+			return fmt.Errorf("failed to write %s: %w (the previous config is in %s)", configPath, err, backupPath)
+		}
+		return fmt.Errorf("failed to write %s: %w (the previous config was kept)", configPath, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Imported configuration from %s into %s\n", path, configPath)
+	if len(issues) > 0 {
+		fmt.Fprintf(&b, "Not applied:\n")
+		for _, issue := range issues {
+			fmt.Fprintf(&b, "  %s\n", issue)
+		}
+	}
+	printOut(b.String())
+	return nil
+}
+
+// validateImport removes entries the helper would reject at run time,
+// returning a description of each
+func validateImport(cfg *config.Config) []string {
+	var issues []string
+
+	for _, id := range sortedKeys(cfg.Modes) {
+		mode := cfg.Modes[id]
+		if mode.ToastPosition != "" && !toast.IsValidPosition(mode.ToastPosition) {
+			issues = append(issues, fmt.Sprintf("modes.%s.toastPosition: invalid position '%s', skipped", id, mode.ToastPosition))
+			mode.ToastPosition = ""
+			cfg.Modes[id] = mode
+		}
+		if _, err := parseToastMessage(mode.ToastMessage); err != nil {
+			issues = append(issues, fmt.Sprintf("modes.%s.toastMessage: %v, skipped", id, err))
+			mode.ToastMessage = ""
+			cfg.Modes[id] = mode
+		}
+	}
+
+	// Modes are checked against the imported config, not the current one
+	manager := modes.NewManager()
+	manager.SetCustomMetadata(customMetadata(cfg))
+
+	for _, name := range sortedKeys(cfg.Presets) {
+		if mode := cfg.Presets[name].PowerMode; mode != "" && !manager.IsValidMode(mode) {
+			issues = append(issues, fmt.Sprintf("presets.%s: unknown power mode '%s', preset skipped", name, mode))
+			delete(cfg.Presets, name)
+			continue
+		}
+		if conflicts := cfg.Presets[name].Conflicts(); len(conflicts) > 0 {
+			issues = append(issues, fmt.Sprintf("presets.%s: %s, preset skipped", name, strings.Join(conflicts, "; ")))
+			delete(cfg.Presets, name)
+		}
+	}
+
+	var rules []config.ScheduleRule
+	for i, rule := range cfg.Schedule {
+		if _, _, err := parseWindow(rule.Window); err != nil {
+			issues = append(issues, fmt.Sprintf("schedule[%d]: %v, rule skipped", i, err))
+			continue
+		}
+		if !manager.IsValidMode(rule.Mode) {
+			issues = append(issues, fmt.Sprintf("schedule[%d]: unknown power mode '%s', rule skipped", i, rule.Mode))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	cfg.Schedule = rules
+
+	for _, seq := range []struct {
+		name string
+		list *[]string
+	}{{"sequence", &cfg.Sequence}, {"sequenceAC", &cfg.SequenceAC}, {"sequenceBattery", &cfg.SequenceBattery}} {
+		var kept []string
+		for _, mode := range *seq.list {
+			if !manager.IsValidMode(mode) {
+				issues = append(issues, fmt.Sprintf("%s: unknown power mode '%s', skipped", seq.name, mode))
+				continue
+			}
+			kept = append(kept, mode)
+		}
+		*seq.list = kept
+	}
+
+	if ms := cfg.OSDDurationMs; ms != 0 && (ms < int(toast.MinDuration.Milliseconds()) || ms > int(toast.MaxDuration.Milliseconds())) {
+		issues = append(issues, fmt.Sprintf("osdDurationMs: %d is out of range, skipped", ms))
+		cfg.OSDDurationMs = 0
+	}
+
+	return issues
+}
@@ -0,0 +1,73 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+)
+
+// writeExport writes an export of cfg under dir and returns its path
+func writeExport(t *testing.T, dir string, cfg *config.Config) string {
+	t.Helper()
+	path := filepath.Join(dir, "export.json")
+	if err := cfg.Export(path); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	return path
+}
+
+func TestConfigImportKeepsBackup(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	previous := []byte(`{"sequence": ["quiet", "performance"]}` + "\n")
+	if err := os.WriteFile(configPath, previous, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	exportPath := writeExport(t, dir, &config.Config{Sequence: []string{"balance"}})
+
+	if err := handleConfigImport(configPath, exportPath); err != nil {
+		t.Fatalf("handleConfigImport: %v", err)
+	}
+
+	backup, err := os.ReadFile(configPath + ".bak")
+	if err != nil || string(backup) != string(previous) {
+		t.Errorf("backup = %q, %v; want the previous config", backup, err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil || len(cfg.Sequence) != 1 || cfg.Sequence[0] != "balance" {
+		t.Errorf("imported config = %+v, %v; want the export", cfg, err)
+	}
+	if _, err := os.Stat(configPath + ".import"); !os.IsNotExist(err) {
+		t.Errorf("temporary import file left behind: %v", err)
+	}
+}
+
+func TestConfigImportFailureKeepsConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	previous := []byte(`{"sequence": ["quiet", "performance"]}` + "\n")
+	if err := os.WriteFile(configPath, previous, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	exportPath := writeExport(t, dir, &config.Config{Sequence: []string{"balance"}})
+
+	// A directory where the temporary file goes makes writing it fail
+	if err := os.Mkdir(configPath+".import", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handleConfigImport(configPath, exportPath); err == nil {
+		t.Fatal("handleConfigImport succeeded, want an error")
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil || string(data) != string(previous) {
+		t.Errorf("config = %q, %v; want it untouched", data, err)
+	}
+	if _, err := os.Stat(configPath + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("backup made for a failed import: %v", err)
+	}
+}
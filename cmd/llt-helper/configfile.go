@@ -0,0 +1,126 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// configFlagPath is the config file given with --config, "" for the default
+var configFlagPath string
+
+// configPathFromArgs returns the config file to load: the one given with
+// --config=PATH (or --config PATH), or config.DefaultPath(). Aliases and the
+// default command come from the config before flags are parsed, so like
+// --safe-mode the flag can appear anywhere and is removed from os.Args.
+func configPathFromArgs() (string, error) {
+	args := os.Args[:1]
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			args = append(args, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(os.Args) {
+				return "", fmt.Errorf("--config needs a file path")
+			}
+			i++
+			value = os.Args[i]
+		}
+		if value == "" {
+			return "", fmt.Errorf("--config needs a file path")
+		}
+		configFlagPath = value
+	}
+	os.Args = args
+
+	if configFlagPath != "" {
+		return configFlagPath, nil
+	}
+	return config.DefaultPath(), nil
+}
+
+// configArgs passes --config on to helper processes this one starts, so
+// they read the same config file
+func configArgs() []string {
+	if configFlagPath == "" {
+		return nil
+	}
+	return []string{"--config=" + configFlagPath}
+}
+
+// configPathSource reports where the config file path came from
+func configPathSource() string {
+	if configFlagPath != "" {
+		return sourceFlag
+	}
+	return envSource("APPDATA")
+}
+
+// newModeManager creates the mode manager described by the config file: its
+// mode metadata and, if given, its default cycle. It reports whether the
+// config's sequence was used.
+func newModeManager(cfg *config.Config) (*modes.Manager, bool) {
+	manager := modes.NewManager()
+	manager.SetCustomMetadata(customMetadata(cfg))
+
+	cycle := configSequence(manager, "sequence", cfg.Sequence)
+	manager.SetCycle(cycle)
+	return manager, len(cycle) > 0
+}
+
+// configSequence returns list, the config's entry key (e.g. "sequence"),
+// skipping (with a warning) modes the manager doesn't know and repeats
+func configSequence(manager *modes.Manager, key string, list []string) []modes.PowerMode {
+	var cycle []modes.PowerMode
+	for _, name := range list {
+		mode := modes.PowerMode(strings.TrimSpace(name))
+		if !manager.IsValidMode(string(mode)) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring unknown mode '%s' in the config's %s\n", name, key)
+			continue
+		}
+		if !slices.Contains(cycle, mode) {
+			cycle = append(cycle, mode)
+		}
+	}
+	if len(list) > 0 && len(cycle) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: the config's %s has no known modes, using the default cycle\n", key)
+	}
+	return cycle
+}
+
+// configDuration returns the config's osdDurationMs as the --toast-duration
+// default, or toast.DefaultDuration when it's unset or out of range
+func configDuration(cfg *config.Config) time.Duration {
+	if cfg.OSDDurationMs == 0 {
+		return toast.DefaultDuration
+	}
+	duration := time.Duration(cfg.OSDDurationMs) * time.Millisecond
+	if duration < toast.MinDuration || duration > toast.MaxDuration {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring osdDurationMs %d in the config (must be between %d and %d)\n",
+			cfg.OSDDurationMs, toast.MinDuration.Milliseconds(), toast.MaxDuration.Milliseconds())
+		return toast.DefaultDuration
+	}
+	return duration
+}
+
+// configToastWait returns the --toast-wait default: true unless the config
+// sets toastWait to false
+func configToastWait(cfg *config.Config) bool {
+	return cfg.ToastWait == nil || *cfg.ToastWait
+}
+
+// configToastOff reports whether the config turns toasts off by default
+func configToastOff(cfg *config.Config) bool {
+	return cfg.Toast != nil && !*cfg.Toast
+}
@@ -0,0 +1,172 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/state"
+)
+
+// Where an effective setting came from
+const (
+	sourceDefault = "default"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+	sourceFile    = "file"
+)
+
+// noConsoleSource records how console attachment was disabled, if it was
+var noConsoleSource = sourceDefault
+
+// configSetting is one resolved setting reported by `config --show`
+type configSetting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// handleConfig implements `config --show`, printing every effective setting
+// and where its value came from, and `config export|import FILE`
+func handleConfig(fs *flag.FlagSet, cfg *config.Config, configPath string, show, jsonOut bool, args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "export":
+		return handleConfigExport(cfg, args[1])
+	case len(args) == 2 && args[0] == "import":
+		return handleConfigImport(configPath, args[1])
+	case !show || len(args) > 0:
+		return fmt.Errorf("usage: config --show [--json] | config export FILE | config import FILE")
+	}
+
+	settings := effectiveSettings(fs, cfg, configPath)
+
+	if jsonOut {
+		data, err := json.Marshal(settings)
+		if err != nil {
+			return fmt.Errorf("failed to encode configuration: %w", err)
+		}
+		printOut(string(data) + "\n")
+		return nil
+	}
+
+	nameWidth, valueWidth := 0, 0
+	for _, s := range settings {
+		nameWidth = max(nameWidth, len(s.Name))
+		valueWidth = max(valueWidth, len(s.Value))
+	}
+
+	var b strings.Builder
+	for _, s := range settings {
+		fmt.Fprintf(&b, "%-*s  %-*s  (%s)\n", nameWidth, s.Name, valueWidth, s.Value, s.Source)
+	}
+	printOut(b.String())
+	return nil
+}
+
+// effectiveSettings resolves paths, flags and config file entries
+func effectiveSettings(fs *flag.FlagSet, cfg *config.Config, configPath string) []configSetting {
+	settings := []configSetting{
+		{"config-path", configPath, configPathSource()},
+		{"state-path", state.DefaultPath(), envSource("LOCALAPPDATA")},
+		{"no-console", fmt.Sprint(noConsoleSource != sourceDefault), noConsoleSource},
+		{"safe-mode", fmt.Sprint(safeModeSource != sourceDefault), safeModeSource},
+	}
+
+	// Flags: everything except the ones that only select what to do
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	fs.VisitAll(func(f *flag.Flag) {
+		switch f.Name {
+		case "help", "h", "show", "json":
+			return
+		}
+		source := sourceDefault
+		if set[f.Name] {
+			source = sourceFlag
+		} else if fileDefaults(cfg)[f.Name] {
+			source = sourceFile
+		}
+		settings = append(settings, configSetting{f.Name, f.Value.String(), source})
+	})
+
+	defaultArgs, defaultSource := defaultCommand(cfg)
+	settings = append(settings, configSetting{"defaultCommand", strings.Join(defaultArgs, " "), defaultSource})
+
+	for _, seq := range []struct {
+		name string
+		list []string
+	}{{"sequence", cfg.Sequence}, {"sequenceAC", cfg.SequenceAC}, {"sequenceBattery", cfg.SequenceBattery}} {
+		source := sourceDefault
+		if len(seq.list) > 0 {
+			source = sourceFile
+		}
+		settings = append(settings, configSetting{seq.name, strings.Join(seq.list, ","), source})
+	}
+
+	presetSource := sourceDefault
+	if len(cfg.PresetFeatures) > 0 {
+		presetSource = sourceFile
+	}
+	settings = append(settings, configSetting{"presetFeatures", strings.Join(cfg.GetPresetFeatures(), ","), presetSource})
+
+	for _, name := range sortedKeys(builtinAliases) {
+		if _, overridden := cfg.Aliases[name]; !overridden {
+			settings = append(settings, configSetting{"aliases." + name, strings.Join(builtinAliases[name], " "), sourceDefault})
+		}
+	}
+	for _, name := range sortedKeys(cfg.Aliases) {
+		settings = append(settings, configSetting{"aliases." + name, strings.Join(cfg.Aliases[name], " "), sourceFile})
+	}
+	for _, name := range sortedKeys(cfg.Modes) {
+		settings = append(settings, configSetting{"modes." + name, compactJSON(cfg.Modes[name]), sourceFile})
+	}
+	for _, name := range sortedKeys(cfg.ModeMap) {
+		settings = append(settings, configSetting{"modeMap." + name, cfg.ModeMap[name], sourceFile})
+	}
+	for _, name := range sortedKeys(cfg.Presets) {
+		settings = append(settings, configSetting{"presets." + name, compactJSON(cfg.Presets[name]), sourceFile})
+	}
+
+	return settings
+}
+
+// fileDefaults returns the flags whose default the config file sets
+func fileDefaults(cfg *config.Config) map[string]bool {
+	return map[string]bool{
+		"no-toast":       cfg.Toast != nil,
+		"toast-duration": cfg.OSDDurationMs != 0,
+		"toast-wait":     cfg.ToastWait != nil,
+	}
+}
+
+// envSource reports a path as coming from the environment when the
+// variable it's derived from is set
+func envSource(name string) string {
+	if os.Getenv(name) != "" {
+		return sourceEnv
+	}
+	return sourceDefault
+}
+
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,66 @@
+//go:build windows
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+)
+
+// confirmOptions holds the --confirm/--yes guard for aggressive modes
+type confirmOptions struct {
+	confirm bool
+	yes     bool
+}
+
+// errDeclined is returned when the user answers no, which isn't a failure
+// worth an error toast
+var errDeclined = errors.New("cancelled")
+
+// check asks before switching to GodMode or another non-built-in (custom)
+// mode when --confirm is set. Without an attached console there's no one to
+// ask, so --yes is required instead.
+func (o confirmOptions) check(manager *modes.Manager, mode string) error {
+	if !o.confirm || o.yes || manager.IsBuiltinMode(mode) {
+		return nil
+	}
+
+	name := manager.GetModeMetadata(modes.PowerMode(mode)).Name
+	ok, err := askYesNo(fmt.Sprintf("Apply %s?", name))
+	if err != nil {
+		return fmt.Errorf("refusing to apply %s: %w", name, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w, %s not applied", errDeclined, name)
+	}
+	return nil
+}
+
+// askYesNo asks a yes/no question on the console, defaulting to no. Without
+// an attached console there's no one to ask, so it fails and the caller
+// should point at --yes.
+func askYesNo(question string) (bool, error) {
+	if consoleHandle == 0 {
+		return false, fmt.Errorf("no console attached to confirm; pass --yes")
+	}
+
+	// Stdin isn't wired up for a GUI-subsystem exe, so read the console directly
+	conin, err := os.OpenFile("CONIN$", os.O_RDWR, 0)
+	if err != nil {
+		return false, fmt.Errorf("cannot read confirmation: %w", err)
+	}
+	defer conin.Close()
+
+	writeToConsole(question + " [y/N] ")
+	answer, _ := bufio.NewReader(conin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,54 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// conflictingSoftware maps the executables of programs known to fight LLT
+// over the power mode to the product they belong to
+var conflictingSoftware = map[string]string{
+	"lenovovantage.exe":              "Lenovo Vantage",
+	"lenovovantageservice.exe":       "Lenovo Vantage",
+	"legionzone.exe":                 "Legion Zone",
+	"lenovo.modern.imcontroller.exe": "Lenovo System Interface Foundation",
+}
+
+// doctorConflict is a running program that may override power mode changes
+type doctorConflict struct {
+	Product string `json:"product"`
+	Process string `json:"process"`
+}
+
+// findConflictingSoftware lists the running processes that belong to
+// conflictingSoftware, each process name once
+func findConflictingSoftware() ([]doctorConflict, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var found []doctorConflict
+	seen := make(map[string]bool)
+
+	entry := windows.ProcessEntry32{Size: uint32(unsafe.Sizeof(windows.ProcessEntry32{}))}
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		name := windows.UTF16ToString(entry.ExeFile[:])
+		product, ok := conflictingSoftware[strings.ToLower(name)]
+		if ok && !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			found = append(found, doctorConflict{Product: product, Process: name})
+		}
+	}
+	if err != windows.ERROR_NO_MORE_FILES {
+		return found, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+)
+
+// parseModesFlag parses the comma-separated --modes list, rejecting unknown
+// modes. An empty flag gives a nil list: the default sequence.
+func parseModesFlag(manager *modes.Manager, modesFlag string) ([]modes.PowerMode, error) {
+	if modesFlag == "" {
+		return nil, nil
+	}
+
+	var allowedModes []modes.PowerMode
+	for _, part := range strings.Split(modesFlag, ",") {
+		trimmed := strings.TrimSpace(part)
+		if trimmed == "" {
+			continue
+		}
+		if !manager.IsValidMode(trimmed) {
+			return nil, fmt.Errorf("invalid mode '%s' in --modes flag", trimmed)
+		}
+		allowedModes = append(allowedModes, modes.PowerMode(trimmed))
+	}
+	if len(allowedModes) == 0 {
+		return nil, fmt.Errorf("no valid modes specified in --modes flag")
+	}
+	return allowedModes, nil
+}
+
+// nextValue returns the value after current, wrapping at the end, like the
+// power mode cycle. A value not in the list (e.g. reported with different
+// casing by an older LLT) lands on the first.
+func nextValue(values []string, current string) string {
+	for i, value := range values {
+		if strings.EqualFold(value, current) {
+			return values[(i+1)%len(values)]
+		}
+	}
+	return values[0]
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/state"
+)
+
+// debounced reports whether a mode change should be skipped because another
+// one happened less than window ago (e.g. a bouncing Stream Deck key). The
+// time is recorded by recordLastMode once a change succeeds, so a failed
+// change can be retried at once; a second press racing this one queues on
+// the instance mutex and sees the time by then.
+func debounced(window time.Duration) bool {
+	if window <= 0 {
+		return false
+	}
+
+	path := state.DefaultPath()
+	st, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	since := time.Since(st.LastChange)
+	return since >= 0 && since < window
+}
@@ -0,0 +1,63 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// handleDebug dispatches the (hidden) debug subcommands. `debug exec get
+// [FEATURE]` and `debug exec set [FEATURE] VALUE` print the llt.exe call
+// the helper would make, without running it; FEATURE defaults to
+// power-mode.
+func handleDebug(client *llt.Client, args []string, jsonOut bool) error {
+	if len(args) < 2 || args[0] != "exec" {
+		return fmt.Errorf("usage: debug exec get [FEATURE] | debug exec set [FEATURE] VALUE")
+	}
+
+	var inv llt.Invocation
+	var err error
+	switch rest := args[2:]; {
+	case args[1] == "get" && len(rest) == 0:
+		inv, err = client.GetInvocation("power-mode")
+	case args[1] == "get" && len(rest) == 1:
+		inv, err = client.GetInvocation(rest[0])
+	case args[1] == "set" && len(rest) == 1:
+		inv, err = client.SetInvocation("power-mode", rest[0])
+	case args[1] == "set" && len(rest) == 2:
+		inv, err = client.SetInvocation(rest[0], rest[1])
+	default:
+		return fmt.Errorf("usage: debug exec get [FEATURE] | debug exec set [FEATURE] VALUE")
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonOut {
+		data, err := json.Marshal(inv)
+		if err != nil {
+			return fmt.Errorf("failed to encode invocation: %w", err)
+		}
+		printOut(string(data) + "\n")
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Path:           %s\n", inv.Path)
+	for i, arg := range inv.Args {
+		fmt.Fprintf(&b, "argv[%d]:        %q\n", i, arg)
+	}
+	fmt.Fprintf(&b, "Dir:            %s\n", inv.Dir)
+	fmt.Fprintf(&b, "Hide window:    %t\n", inv.HideWindow)
+	fmt.Fprintf(&b, "Creation flags: 0x%08X\n", inv.CreationFlags)
+	fmt.Fprintf(&b, "Environment:    %d variables\n", len(inv.Env))
+	for _, kv := range inv.Env {
+		fmt.Fprintf(&b, "  %s\n", kv)
+	}
+	printOut(b.String())
+	return nil
+}
@@ -0,0 +1,254 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+)
+
+// doctorReport is the result of the doctor command's environment checks
+type doctorReport struct {
+	Version        string          `json:"version"`
+	HelperArch     string          `json:"helperArch"`
+	LLTArch        string          `json:"lltArch,omitempty"`
+	LLTPath        string          `json:"lltPath,omitempty"`
+	LLTFound       bool            `json:"lltFound"`
+	CLIResponding  bool            `json:"cliResponding"`
+	CurrentMode    string          `json:"currentMode,omitempty"`
+	AvailableModes []string        `json:"availableModes,omitempty"`
+	Problems       []string        `json:"problems,omitempty"`
+	Calls          []doctorCall    `json:"calls"`
+	Assets         []doctorAsset   `json:"assets"`
+	Features       []doctorFeature `json:"features,omitempty"`
+
+	// ConflictingSoftware lists running programs that may override the
+	// power mode behind LLT's back
+	ConflictingSoftware []doctorConflict `json:"conflictingSoftware,omitempty"`
+}
+
+// doctorFeature is whether one LLT feature is available, and how that was
+// determined (from LLT's settings file, or by asking llt.exe)
+type doctorFeature struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Source    string `json:"source"`
+}
+
+// doctorAsset is the result of checking one mode's asset file
+type doctorAsset struct {
+	Mode  string `json:"mode"`
+	Kind  string `json:"kind"`
+	Path  string `json:"path"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// doctorCall is the timing of one llt.exe invocation made during the checks
+type doctorCall struct {
+	Args       string  `json:"args"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// handleDoctor checks the LLT installation and CLI, reporting what it finds
+// (including how long each llt.exe call took) instead of failing on the
+// first problem. clientErr is the error from creating the client, if any.
+func handleDoctor(client *llt.Client, clientErr error, manager *modes.Manager, jsonOut bool) error {
+	report := doctorReport{Version: version, HelperArch: runtime.GOARCH, Calls: []doctorCall{}, Assets: checkAssets(manager)}
+	for _, asset := range report.Assets {
+		if !asset.OK {
+			report.Problems = append(report.Problems, fmt.Sprintf("%s %s for %s: %s", asset.Kind, asset.Path, asset.Mode, asset.Error))
+		}
+	}
+
+	conflicts, err := findConflictingSoftware()
+	if err != nil {
+		report.Problems = append(report.Problems, err.Error())
+	}
+	report.ConflictingSoftware = conflicts
+	for _, c := range conflicts {
+		report.Problems = append(report.Problems, fmt.Sprintf(
+			"%s is running (%s) and may override power mode changes, making the mode change back; close it or disable its power settings",
+			c.Product, c.Process))
+	}
+
+	if clientErr != nil {
+		report.Problems = append(report.Problems, clientErr.Error())
+	} else {
+		report.LLTPath = client.Path()
+		report.LLTFound = true
+		report.LLTArch = checkArchitecture(client, &report)
+		report.CLIResponding = client.IsRunning()
+
+		if !report.CLIResponding {
+			report.Problems = append(report.Problems, "LLT not running or CLI disabled")
+		} else {
+			if current, err := client.GetCurrentMode(); err != nil {
+				report.Problems = append(report.Problems, err.Error())
+			} else {
+				report.CurrentMode = current
+			}
+
+			if available, err := client.ListAvailableModes(); err != nil {
+				report.Problems = append(report.Problems, err.Error())
+			} else {
+				report.AvailableModes = available
+			}
+
+			for _, f := range client.DetectFeatures(llt.KnownFeatures) {
+				report.Features = append(report.Features, doctorFeature{Name: f.Name, Available: f.Available, Source: f.Source})
+			}
+		}
+
+		for _, call := range client.Calls() {
+			dc := doctorCall{
+				Args:       strings.Join(call.Args, " "),
+				DurationMs: float64(call.Duration.Microseconds()) / 1000,
+			}
+			if call.Err != nil {
+				dc.Error = call.Err.Error()
+			}
+			report.Calls = append(report.Calls, dc)
+		}
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		printOut(string(data) + "\n")
+		return nil
+	}
+
+	printOut(formatDoctorReport(report))
+	return nil
+}
+
+// checkArchitecture reads llt.exe's architecture, noting a mismatch with
+// the helper's own, which can change how WMI and feature calls behave
+func checkArchitecture(client *llt.Client, report *doctorReport) string {
+	arch, err := client.Architecture()
+	if err != nil {
+		report.Problems = append(report.Problems, err.Error())
+		return ""
+	}
+
+	if arch != llt.ArchAnyCPU && arch != report.HelperArch {
+		report.Problems = append(report.Problems, fmt.Sprintf(
+			"LLT is built for %s but llt-helper for %s; WMI reads and feature calls may behave differently (use the %s build of llt-helper)",
+			arch, report.HelperArch, arch))
+	}
+	return arch
+}
+
+// checkAssets verifies that each mode's icon exists and is readable. Modes
+// without an icon (custom modes that don't configure one) are skipped.
+func checkAssets(manager *modes.Manager) []doctorAsset {
+	assets := []doctorAsset{}
+
+	for _, mode := range manager.Modes() {
+		meta := manager.GetModeMetadata(mode)
+		if meta.IconPath == "" {
+			continue
+		}
+
+		asset := doctorAsset{Mode: string(mode), Kind: "icon", Path: meta.IconPath, OK: true}
+		if f, err := os.Open(meta.IconPath); err != nil {
+			asset.OK = false
+			asset.Error = err.Error()
+			if errors.Is(err, fs.ErrNotExist) {
+				asset.Error = "missing"
+			}
+		} else {
+			f.Close()
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets
+}
+
+// formatDoctorReport renders the report as human-readable text
+func formatDoctorReport(report doctorReport) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "llt-helper version %s (%s)\n", report.Version, report.HelperArch)
+	if report.LLTFound {
+		fmt.Fprintf(&b, "LLT path:        %s\n", report.LLTPath)
+	} else {
+		fmt.Fprintf(&b, "LLT path:        not found\n")
+	}
+	if report.LLTArch != "" {
+		fmt.Fprintf(&b, "LLT arch:        %s\n", report.LLTArch)
+	}
+	fmt.Fprintf(&b, "CLI responding:  %t\n", report.CLIResponding)
+	if report.CurrentMode != "" {
+		fmt.Fprintf(&b, "Current mode:    %s\n", report.CurrentMode)
+	}
+	if len(report.AvailableModes) > 0 {
+		fmt.Fprintf(&b, "Available modes: %s\n", strings.Join(report.AvailableModes, ", "))
+	}
+
+	if len(report.Features) > 0 {
+		fmt.Fprintf(&b, "Features:\n")
+		for _, f := range report.Features {
+			status := "unavailable"
+			if f.Available {
+				status = "available"
+			}
+			fmt.Fprintf(&b, "  %-26s %s (%s)\n", f.Name, status, f.Source)
+		}
+	}
+
+	if len(report.Calls) > 0 {
+		fmt.Fprintf(&b, "LLT calls:\n")
+		for _, call := range report.Calls {
+			d := time.Duration(call.DurationMs * float64(time.Millisecond)).Round(time.Millisecond)
+			fmt.Fprintf(&b, "  %-32s %s", call.Args, d)
+			if call.Error != "" {
+				fmt.Fprintf(&b, " (%s)", call.Error)
+			}
+			fmt.Fprintln(&b)
+		}
+	}
+
+	if len(report.ConflictingSoftware) > 0 {
+		fmt.Fprintf(&b, "Conflicting software:\n")
+		for _, c := range report.ConflictingSoftware {
+			fmt.Fprintf(&b, "  %-34s %s\n", c.Product, c.Process)
+		}
+	}
+
+	if len(report.Assets) > 0 {
+		fmt.Fprintf(&b, "Assets:\n")
+		for _, asset := range report.Assets {
+			status := "ok"
+			if !asset.OK {
+				status = asset.Error
+			}
+			fmt.Fprintf(&b, "  %-12s %s %s (%s)\n", asset.Mode, asset.Kind, asset.Path, status)
+		}
+	}
+
+	if len(report.Problems) == 0 {
+		fmt.Fprintf(&b, "No problems found\n")
+	} else {
+		fmt.Fprintf(&b, "Problems:\n")
+		for _, p := range report.Problems {
+			fmt.Fprintf(&b, "  - %s\n", p)
+		}
+	}
+
+	return b.String()
+}
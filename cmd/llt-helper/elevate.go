@@ -0,0 +1,85 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// elevatedFlag is passed to the relaunched helper so that, if it still
+// can't do the operation, it reports the error instead of relaunching again
+const elevatedFlag = "--elevated"
+
+// elevateOptions holds --elevate and the internal --elevated marker
+type elevateOptions struct {
+	elevate  bool
+	elevated bool
+}
+
+// maybeElevate relaunches the helper as administrator when err says the
+// operation needs it and --elevate was given, then exits this process. It
+// returns (leaving err to the caller) when relaunching doesn't apply.
+func maybeElevate(err error, opts elevateOptions) {
+	if !errors.Is(err, llt.ErrElevationRequired) || !opts.elevate || opts.elevated {
+		return
+	}
+	if windows.GetCurrentProcessToken().IsElevated() {
+		return
+	}
+
+	if err := relaunchElevated(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitFailed)
+	}
+	printOut("Continuing as administrator in a new helper process\n")
+	os.Exit(0)
+}
+
+// relaunchElevated starts the helper again through the UAC prompt (the
+// "runas" verb) with the same arguments plus elevatedFlag
+func relaunchElevated(args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the helper executable: %w", err)
+	}
+
+	// The marker goes right after the command word, since flags after a
+	// positional argument aren't parsed
+	relaunch := append([]string{}, args[:1]...)
+	relaunch = append(relaunch, elevatedFlag)
+	relaunch = append(relaunch, configArgs()...)
+	relaunch = append(relaunch, args[1:]...)
+
+	quoted := make([]string, len(relaunch))
+	for i, arg := range relaunch {
+		quoted[i] = syscall.EscapeArg(arg)
+	}
+
+	verb, _ := windows.UTF16PtrFromString("runas") // constants, cannot contain NUL
+	file, err := windows.UTF16PtrFromString(exe)
+	if err != nil {
+		return fmt.Errorf("invalid executable path: %w", err)
+	}
+	params, err := windows.UTF16PtrFromString(strings.Join(quoted, " "))
+	if err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	cwd, _ := os.Getwd() // "" lets Windows pick the directory
+	dir, _ := windows.UTF16PtrFromString(cwd)
+
+	if err := windows.ShellExecute(0, verb, file, params, dir, windows.SW_SHOWNORMAL); err != nil {
+		if errors.Is(err, windows.ERROR_CANCELLED) {
+			return fmt.Errorf("elevation was declined at the UAC prompt")
+		}
+		return fmt.Errorf("failed to relaunch as administrator: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// handleEnableCLI switches on LLT's CLI setting and probes the CLI to see
+// whether it took effect
+func handleEnableCLI(client *llt.Client) error {
+	if client.IsRunning() {
+		printOut("LLT CLI is already enabled\n")
+		return nil
+	}
+
+	path := llt.SettingsPath()
+	if err := llt.EnableCLI(path); err != nil {
+		return err
+	}
+	printOut(fmt.Sprintf("Enabled the CLI in %s\n", path))
+
+	if client.IsRunning() {
+		printOut("LLT CLI is responding\n")
+		return nil
+	}
+
+	// LLT keeps its settings in memory and may write the old value back on
+	// exit, so it has to be closed fully before the change sticks
+	printOut("LLT CLI is not responding yet: exit LLT completely (tray icon → Exit), run enable-cli again if needed, then start LLT\n")
+	return nil
+}
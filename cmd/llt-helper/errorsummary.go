@@ -0,0 +1,50 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// errorSummary counts transient errors in long-running modes and shows one
+// OSD per interval summarizing them, instead of a toast per error. A zero
+// interval disables the summary.
+type errorSummary struct {
+	interval time.Duration
+	notifier toast.Notifier
+	count    int
+	since    time.Time
+}
+
+func newErrorSummary(interval time.Duration, notifier toast.Notifier) *errorSummary {
+	return &errorSummary{interval: interval, notifier: notifier, since: time.Now()}
+}
+
+// record counts an error for the next summary
+func (s *errorSummary) record() {
+	s.count++
+}
+
+// flush shows the summary once the interval has passed, then resets the count
+func (s *errorSummary) flush() {
+	if s.interval <= 0 || time.Since(s.since) < s.interval {
+		return
+	}
+
+	if s.count > 0 {
+		message := fmt.Sprintf("%d LLT errors in the last %s", s.count, s.interval)
+		if s.count == 1 {
+			message = fmt.Sprintf("1 LLT error in the last %s", s.interval)
+		}
+		if err := s.notifier.ShowError(message); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+		}
+	}
+
+	s.count = 0
+	s.since = time.Now()
+}
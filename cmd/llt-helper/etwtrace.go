@@ -0,0 +1,63 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/etw"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// etwTracer writes --etw events: the command starting and stopping, each
+// llt.exe invocation, and errors. A nil etwTracer writes nothing.
+type etwTracer struct {
+	provider *etw.Provider
+	command  string
+	start    time.Time
+}
+
+// newETWTracer registers the ETW provider and writes the start event, or
+// returns nil when --etw isn't given or registration fails
+func newETWTracer(enabled bool, command string, args []string) *etwTracer {
+	if !enabled {
+		return nil
+	}
+	provider, err := etw.Register()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; --etw ignored\n", err)
+		return nil
+	}
+
+	t := &etwTracer{provider: provider, command: command, start: time.Now()}
+	provider.Write(etw.LevelInfo, fmt.Sprintf("start %s %s (pid %d)", command, strings.Join(args, " "), os.Getpid()))
+	return t
+}
+
+// call writes an llt.exe invocation, at error level when it failed
+func (t *etwTracer) call(call llt.Call) {
+	if t == nil {
+		return
+	}
+	msg := fmt.Sprintf("llt.exe %s took %s", strings.Join(call.Args, " "), call.Duration.Round(time.Millisecond))
+	if call.Err != nil {
+		t.provider.Write(etw.LevelError, fmt.Sprintf("%s: %v", msg, call.Err))
+		return
+	}
+	t.provider.Write(etw.LevelVerbose, msg)
+}
+
+// stop writes the command's error, if any, and its end, then unregisters
+func (t *etwTracer) stop(err error, exitCode int) {
+	if t == nil {
+		return
+	}
+	if err != nil {
+		t.provider.Write(etw.LevelError, fmt.Sprintf("%s failed: %v", t.command, err))
+	}
+	t.provider.Write(etw.LevelInfo, fmt.Sprintf("stop %s: exit code %d after %s", t.command, exitCode, time.Since(t.start).Round(time.Millisecond)))
+	t.provider.Close()
+}
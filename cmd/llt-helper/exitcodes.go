@@ -0,0 +1,42 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// Exit codes, documented in the README's Exit Codes table. Scripts rely on
+// them, so existing values must not change; new failure classes get new codes.
+const (
+	exitOK              = 0  // success, or help was printed
+	exitLLTNotRunning   = 1  // LLT installed but its CLI isn't responding
+	exitUsage           = 2  // invalid command-line arguments
+	exitUnknownMode     = 3  // unknown power mode
+	exitFailed          = 4  // the command (e.g. setting the mode) failed
+	exitLocked          = 5  // power mode is locked
+	exitAnotherInstance = 6  // another helper was still changing settings
+	exitNoop            = 7  // --exit-on-noop found the mode already active
+	exitLLTNotFound     = 8  // Legion Toolkit isn't installed
+	exitLLTBlocked      = 9  // llt.exe can't be run (antivirus, permissions, needs admin)
+	exitLLTUpdating     = 10 // Legion Toolkit is updating itself
+)
+
+// exitCode picks the exit code for a command that failed with err
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUnknownMode):
+		return exitUnknownMode
+	case errors.Is(err, llt.ErrLLTNotFound):
+		return exitLLTNotFound
+	case errors.Is(err, llt.ErrLLTNotExecutable), errors.Is(err, llt.ErrElevationRequired):
+		return exitLLTBlocked
+	case errors.Is(err, llt.ErrLLTBusyUpdating):
+		return exitLLTUpdating
+	}
+	return exitFailed
+}
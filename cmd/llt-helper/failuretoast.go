@@ -0,0 +1,49 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// failureToastCommands show an error toast when they fail. Started from a
+// Stream Dock key there's no console, so stderr alone would go unseen.
+var failureToastCommands = map[string]bool{
+	"toggle": true,
+	"next":   true,
+	"set":    true,
+}
+
+// showFailure shows err as an error toast if command is one of
+// failureToastCommands. With --no-toast the notifier shows nothing.
+func showFailure(notifier toast.Notifier, command string, err error) {
+	if !failureToastCommands[command] || errors.Is(err, errDeclined) {
+		return
+	}
+	if toastErr := notifier.ShowError(failureMessage(err)); toastErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", toastErr)
+	}
+}
+
+// failureMessage returns the toast text for err: a short explanation for
+// the LLT problems a user can act on, otherwise the error itself
+func failureMessage(err error) string {
+	switch {
+	case errors.Is(err, llt.ErrLLTBusyUpdating):
+		return "LLT is updating, try again shortly"
+	case errors.Is(err, llt.ErrBusy):
+		return "LLT is busy, try again"
+	case errors.Is(err, llt.ErrFeatureUnsupported):
+		return "Power modes aren't supported on this device"
+	case errors.Is(err, llt.ErrElevationRequired):
+		return "Administrator rights required (see --elevate)"
+	case errors.Is(err, llt.ErrLLTNotExecutable):
+		return "llt.exe can't be run (blocked by antivirus?)"
+	}
+	return err.Error()
+}
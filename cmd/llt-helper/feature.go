@@ -0,0 +1,33 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// handleFeature dispatches `feature get NAME` and `feature set NAME VALUE`,
+// passing any LLT feature (e.g. refresh-rate) straight through to
+// `llt.exe f get/set`. get prints just the value, so scripts can use it.
+func handleFeature(client *llt.Client, args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "get":
+		value, err := client.GetFeature(args[1])
+		if err != nil {
+			return err
+		}
+		printOut(value + "\n")
+		return nil
+
+	case len(args) == 3 && args[0] == "set":
+		if err := client.SetFeature(args[1], args[2]); err != nil {
+			return err
+		}
+		printOut(fmt.Sprintf("Set %s to %s\n", args[1], args[2]))
+		return nil
+	}
+
+	return fmt.Errorf("usage: feature get NAME | feature set NAME VALUE")
+}
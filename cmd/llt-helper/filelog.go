@@ -0,0 +1,159 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// logFileEnv names a log file like --log-file, for launchers that can't
+// add flags to every button
+const logFileEnv = "LLT_HELPER_LOG"
+
+// fileLogger appends timestamped --log-file entries: the command and its
+// result, where LLT was found, each llt.exe call and mode change, and every
+// toast. With --verbose it also logs each llt.exe command line. A nil
+// fileLogger writes nothing.
+type fileLogger struct {
+	path    string
+	verbose bool
+	command string
+	start   time.Time
+
+	// failed is set once the file couldn't be written, so the warning
+	// appears once rather than for every entry
+	failed bool
+}
+
+// fileLog is the process's --log-file logger, nil when logging is off
+var fileLog *fileLogger
+
+// newFileLogger returns a logger for path (LLT_HELPER_LOG when empty) and
+// writes the start entry, or returns nil when neither is set
+func newFileLogger(path string, verbose bool, command string, args []string) *fileLogger {
+	if path == "" {
+		path = os.Getenv(logFileEnv)
+	}
+	if path == "" {
+		return nil
+	}
+
+	l := &fileLogger{path: path, verbose: verbose, command: command, start: time.Now()}
+	l.info("start %s %s (pid %d)", command, strings.Join(args, " "), os.Getpid())
+	return l
+}
+
+// write appends one entry. The file is opened per entry, so nothing is left
+// open when the helper exits early and helpers started together append
+// whole lines instead of overwriting each other.
+func (l *fileLogger) write(level, format string, args ...any) {
+	if l == nil || l.failed {
+		return
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: can't write log file: %v; --log-file ignored\n", err)
+		l.failed = true
+		return
+	}
+	defer f.Close()
+
+	line := fmt.Sprintf("%s %-5s %s\n", time.Now().Format("2006-01-02T15:04:05.000"), level, fmt.Sprintf(format, args...))
+	if _, err := f.WriteString(line); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: can't write log file: %v\n", err)
+	}
+}
+
+// info logs a key event
+func (l *fileLogger) info(format string, args ...any) {
+	l.write("INFO", format, args...)
+}
+
+// debug logs detail that only --verbose asks for
+func (l *fileLogger) debug(format string, args ...any) {
+	if l != nil && l.verbose {
+		l.write("DEBUG", format, args...)
+	}
+}
+
+// error logs a failure
+func (l *fileLogger) error(format string, args ...any) {
+	l.write("ERROR", format, args...)
+}
+
+// client logs which llt.exe the command uses, or why none could be found
+func (l *fileLogger) client(client *llt.Client, err error) {
+	if err != nil {
+		l.error("LLT: %v", err)
+		return
+	}
+	l.info("LLT at %s", client.Path())
+}
+
+// call logs an llt.exe invocation with its exit status and output, and the
+// full command line with --verbose
+func (l *fileLogger) call(path string, call llt.Call) {
+	if l == nil {
+		return
+	}
+	l.debug("exec %q %s took %s", path, strings.Join(call.Args, " "), call.Duration.Round(time.Millisecond))
+
+	args := strings.Join(call.Args, " ")
+	if call.Err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(call.Err, &exitErr) {
+			l.error("llt.exe %s: exit code %d: %v", args, exitErr.ExitCode(), call.Err)
+			return
+		}
+		l.error("llt.exe %s: %v", args, call.Err)
+		return
+	}
+	l.info("llt.exe %s: exit code 0, output %q", args, call.Output)
+}
+
+// stop logs the command's error, if any, and its exit code
+func (l *fileLogger) stop(err error, exitCode int) {
+	if l == nil {
+		return
+	}
+	if err != nil {
+		l.error("%s failed: %v", l.command, err)
+	}
+	l.info("stop %s: exit code %d after %s", l.command, exitCode, time.Since(l.start).Round(time.Millisecond))
+}
+
+// loggedNotifier logs each toast and whether it could be shown
+type loggedNotifier struct {
+	toast.Notifier
+}
+
+func (n loggedNotifier) ShowModeChange(message, iconPath, color, position string) error {
+	return logToast("mode change", message, n.Notifier.ShowModeChange(message, iconPath, color, position))
+}
+
+func (n loggedNotifier) ShowError(message string) error {
+	return logToast("error", message, n.Notifier.ShowError(message))
+}
+
+func (n loggedNotifier) Show(title, message string) error {
+	return logToast(title, message, n.Notifier.Show(title, message))
+}
+
+// logToast logs a toast's outcome and passes its error through
+func logToast(kind, message string, err error) error {
+	message = strings.ReplaceAll(message, "\n", " / ")
+	if err != nil {
+		fileLog.error("%s toast %q failed: %v", kind, message, err)
+	} else {
+		fileLog.info("%s toast %q shown", kind, message)
+	}
+	return err
+}
@@ -0,0 +1,245 @@
+//go:build windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// commandHelp is the focused help shown by `llt-helper COMMAND --help`
+type commandHelp struct {
+	usage    string   // arguments after the program name
+	summary  string   // one line describing the command
+	flags    []string // names of the flags relevant to the command
+	examples []string // arguments after the program name
+}
+
+// Flags shared by groups of commands
+var (
+	toastFlags  = []string{"no-toast", "toast-position", "toast-animation", "toast-multiline", "toast-text-shadow", "toast-no-topmost", "toast-monitor", "toast-scale", "toast-theme", "toast-progress", "toast-max-width", "toast-show-battery", "toast-delay", "toast-duration", "toast-cooldown", "toast-wait", "osd-async", "toast-stack", "icon-theme"}
+	clientFlags = []string{"timeout", "max-rate", "retries", "retry-delay", "wait-for-llt", "llt-path", "verbose", "etw", "log-file", "llt-arg", "mode-map", "elevate"}
+)
+
+func flagList(groups ...[]string) []string {
+	var names []string
+	for _, group := range groups {
+		names = append(names, group...)
+	}
+	return names
+}
+
+var commandHelps = map[string]commandHelp{
+	"toggle": {
+		usage:    "toggle [flags]",
+		summary:  "Cycle to the next power mode in the sequence (or the --modes list).",
+		flags:    flagList([]string{"modes", "modes-ac", "modes-battery", "reverse", "unknown-fallback", "confirm", "yes", "force", "debounce", "only-on", "read-source", "broadcast", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"toggle", "toggle --modes=quiet,performance", "toggle --reverse", "toggle --no-toast --debounce=500ms", "toggle --only-on=battery", "toggle --modes-battery=quiet,balance --modes-ac=balance,performance"},
+	},
+	"next": {
+		usage:    "next [flags]",
+		summary:  "Move forward through the --modes list (or the sequence), skipping modes this device doesn't offer, and print the landing mode.",
+		flags:    flagList([]string{"modes", "modes-ac", "modes-battery", "unknown-fallback", "confirm", "yes", "force", "debounce", "only-on", "read-source", "broadcast", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"next", "next --modes=quiet,performance,godmode"},
+	},
+	"set": {
+		usage:    "set --mode=MODE [flags]",
+		summary:  "Set a specific power mode, by name, LLT index or position in the cycle.",
+		flags:    flagList([]string{"mode", "mode-index", "index", "revert-to", "revert-after", "confirm", "yes", "force", "exit-on-noop", "debounce", "only-on", "broadcast", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"set --mode=balance", "set --mode-index=3", "set --index=2", "set --mode=godmode --confirm", "set --mode=- < mode.txt", "set --mode=performance --revert-to=balance --revert-after=10m"},
+	},
+	"auto": {
+		usage:    "auto --on-ac=MODE --on-battery=MODE [--apply] [flags]",
+		summary:  "Pick the mode for the current power source. With --apply, set it (skipped when already active, OSD shown otherwise); without, only print it.",
+		flags:    flagList([]string{"on-ac", "on-battery", "apply", "confirm", "yes", "force", "exit-on-noop", "debounce", "broadcast", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"auto --on-ac=performance --on-battery=quiet --apply", "auto --on-ac=performance --on-battery=quiet"},
+	},
+	"status": {
+		usage:    "status [flags]",
+		summary:  "Show the current power mode.",
+		flags:    flagList([]string{"json", "short", "icon", "toast", "format", "write", "write-format", "with-timestamp", "local-time", "strict", "read-source"}, toastFlags, clientFlags),
+		examples: []string{"status", "status --short", "status --json", "status --toast", `status --format="{{.Name}} ({{.Mode}})"`, "status --read-source=auto", "status --json --with-timestamp"},
+	},
+	"list": {
+		usage:    "list [flags]",
+		summary:  "List the known modes (the cycle plus modes configured in the config file), marking the current one.",
+		flags:    flagList([]string{"available-only", "order", "json", "icon-theme"}, clientFlags),
+		examples: []string{"list", "list --available-only --json", "list --available-only --order=canonical"},
+	},
+	"modes": {
+		usage:    "modes [--graph] [--modes=LIST] [flags]",
+		summary:  "Show the cycle toggle follows, with the current mode marked. Changes nothing, so --modes can be previewed.",
+		flags:    flagList([]string{"graph", "modes", "read-source"}, clientFlags),
+		examples: []string{"modes", "modes --graph", "modes --graph --modes=quiet,performance"},
+	},
+	"lock": {
+		usage:    "lock --mode=MODE [flags]",
+		summary:  "Set a mode and refuse toggle/set (without --force) until unlock.",
+		flags:    flagList([]string{"mode", "broadcast", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"lock --mode=performance"},
+	},
+	"unlock": {
+		usage:    "unlock",
+		summary:  "Remove the lock set by lock.",
+		examples: []string{"unlock"},
+	},
+	"reset-state": {
+		usage:    "reset-state [--yes]",
+		summary:  "Delete the helper's state file (lock, last mode, debounce and cooldown times), listing what it held. Device settings and the config file are kept.",
+		flags:    []string{"yes", "single-instance"},
+		examples: []string{"reset-state", "reset-state --yes"},
+	},
+	"test-osd": {
+		usage:    "test-osd [flags]",
+		summary:  "Show a sample toast with the given toast settings, without touching LLT.",
+		flags:    []string{"title", "message", "osd-icon", "osd-color", "toast-position", "toast-animation", "toast-multiline", "toast-text-shadow", "toast-no-topmost", "toast-monitor", "toast-scale", "toast-theme", "toast-progress", "toast-max-width", "toast-delay", "toast-duration"},
+		examples: []string{"test-osd --toast-position=top-right", `test-osd --message="Switched to Quiet Mode" --toast-animation=fade`, `test-osd --osd-icon=assets\icons\quiet.png --osd-color=#4A90E2`},
+	},
+	"monitors": {
+		usage:    "monitors [flags]",
+		summary:  "List monitors by the number --toast-monitor takes, with resolution and work area.",
+		flags:    []string{"json", "identify", "toast-position"},
+		examples: []string{"monitors", "monitors --identify", "monitors --json"},
+	},
+	"whereis": {
+		usage:    "whereis [flags]",
+		summary:  "Show where llt.exe was looked for, in order, and which one is used.",
+		flags:    []string{"json", "llt-path"},
+		examples: []string{"whereis", "whereis --json"},
+	},
+	"config": {
+		usage:    "config --show [flags] | config export FILE | config import FILE",
+		summary:  "Print every effective setting and where it came from (default, env, flag or file), or copy the config file's settings between machines.",
+		flags:    []string{"show", "json"},
+		examples: []string{"config --show", "config --show --icon-theme=dark --json", "config export helper.json", "config import helper.json"},
+	},
+	"enable-cli": {
+		usage:    "enable-cli",
+		summary:  "Turn on the LLT CLI setting in LLT's settings file (restart LLT afterwards).",
+		flags:    []string{"elevate"},
+		examples: []string{"enable-cli", "enable-cli --elevate"},
+	},
+	"doctor": {
+		usage:    "doctor [flags]",
+		summary:  "Check the LLT installation, CLI and icon files, with llt.exe call timings.",
+		flags:    flagList([]string{"json", "icon-theme"}, clientFlags),
+		examples: []string{"doctor", "doctor --json"},
+	},
+	"watch": {
+		usage:    "watch [flags]",
+		summary:  "Poll the power mode until stopped, optionally enforcing a mode or a schedule.",
+		flags:    flagList([]string{"interval", "json", "enforce", "cooldown", "toast-on-enforce", "toast-on-change", "toast-source", "schedule", "features", "error-summary-interval", "write", "write-format", "with-timestamp", "local-time", "broadcast"}, toastFlags, clientFlags),
+		examples: []string{"watch --enforce=performance --cooldown=30s", "watch --schedule", "watch --features=power-mode,battery --with-timestamp", "watch --json --interval=1s"},
+	},
+	"sensors": {
+		usage:    "sensors [flags]",
+		summary:  "Show CPU/GPU temperatures and fan speeds, if LLT exposes them.",
+		flags:    flagList([]string{"json"}, clientFlags),
+		examples: []string{"sensors", "sensors --json"},
+	},
+	"backlight": {
+		usage:    "backlight [--toggle] [flags]",
+		summary:  "Show the keyboard backlight level, or cycle through the levels the device supports.",
+		flags:    flagList([]string{"toggle", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"backlight", "backlight --toggle"},
+	},
+	"refresh-rate": {
+		usage:    "refresh-rate [--toggle] [flags]",
+		summary:  "Show the panel refresh rate, or cycle through the rates the panel supports.",
+		flags:    flagList([]string{"toggle", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"refresh-rate", "refresh-rate --toggle"},
+	},
+	"serve": {
+		usage:    "serve [flags]",
+		summary:  `Answer status, list, toggle, set and subscribe requests on \\.\pipe\llt-helper until stopped.`,
+		flags:    flagList([]string{"modes", "interval", "error-summary-interval"}, toastFlags, clientFlags),
+		examples: []string{"serve --interval=1s"},
+	},
+	"schedule": {
+		usage:    "schedule [flags]",
+		summary:  "Apply the mode of the config file's schedule rule that is active now.",
+		flags:    flagList([]string{"broadcast"}, toastFlags, clientFlags),
+		examples: []string{"schedule"},
+	},
+	"hud": {
+		usage:    "hud [flags]",
+		summary:  "Show a persistent on-screen indicator of the current mode until stopped.",
+		flags:    flagList([]string{"interval", "icon-theme"}, clientFlags),
+		examples: []string{"hud --interval=1s"},
+	},
+	"tray": {
+		usage:    "tray [flags]",
+		summary:  "Show the current mode in the notification area: click to toggle, right-click to set a mode.",
+		flags:    flagList([]string{"interval", "modes", "icon-theme"}, toastFlags, clientFlags),
+		examples: []string{"tray", "tray --modes=quiet,performance --no-toast"},
+	},
+	"profile": {
+		usage:    "profile list | profile set NAME",
+		summary:  "List or run LLT automation profiles (Quick Actions).",
+		flags:    flagList([]string{"single-instance"}, clientFlags),
+		examples: []string{"profile list", `profile set "Gaming"`},
+	},
+	"feature": {
+		usage:    "feature get NAME | feature set NAME VALUE",
+		summary:  "Read or change any LLT feature, as llt.exe f get/set does.",
+		flags:    flagList([]string{"single-instance"}, clientFlags),
+		examples: []string{"feature get refresh-rate", "feature set refresh-rate 165"},
+	},
+	"preset": {
+		usage:    "preset NAME | preset save NAME",
+		summary:  "Apply a saved preset, or save the current mode and features as one.",
+		flags:    flagList([]string{"broadcast", "single-instance"}, toastFlags, clientFlags),
+		examples: []string{"preset save work", "preset work"},
+	},
+	"benchmark": {
+		usage:    "benchmark [flags]",
+		summary:  "Measure get/set latency and process spawn overhead (maintainer tool).",
+		flags:    flagList([]string{"count", "mode", "dry-run", "json"}, clientFlags),
+		examples: []string{"benchmark --count=20 --dry-run"},
+	},
+	"debug": {
+		usage:    "debug exec get [FEATURE] | debug exec set [FEATURE] VALUE",
+		summary:  "Print the llt.exe path, arguments, directory and environment a get or set would use, without running it (troubleshooting tool).",
+		flags:    []string{"json", "llt-arg", "wait-for-llt"},
+		examples: []string{"debug exec get", "debug exec set performance", "debug --json exec get battery"},
+	},
+}
+
+// printCommandUsage prints the help for one command, or the overview when
+// the command has no dedicated help
+func printCommandUsage(fs *flag.FlagSet, command string) {
+	help, ok := commandHelps[command]
+	if !ok {
+		printUsage()
+		return
+	}
+
+	prog := programName()
+	var b strings.Builder
+	fmt.Fprintf(&b, "Usage: %s %s\n\n%s\n", prog, help.usage, help.summary)
+
+	if len(help.flags) > 0 {
+		fmt.Fprintf(&b, "\nFlags:\n")
+		for _, name := range help.flags {
+			f := fs.Lookup(name)
+			if f == nil {
+				continue
+			}
+			kind, usage := flag.UnquoteUsage(f)
+			fmt.Fprintf(&b, "  --%-24s %s", strings.TrimSpace(name+" "+kind), usage)
+			if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "0s" && f.DefValue != "false" {
+				fmt.Fprintf(&b, " (default %s)", f.DefValue)
+			}
+			fmt.Fprintln(&b)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nExamples:\n")
+	for _, example := range help.examples {
+		fmt.Fprintf(&b, "  %s %s\n", prog, example)
+	}
+	fmt.Fprintf(&b, "\nRun '%s --help' for all commands.\n", prog)
+
+	printOut(b.String())
+}
@@ -0,0 +1,89 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/state"
+)
+
+// --unknown-fallback values
+const (
+	fallbackFirst   = "first"
+	fallbackBalance = "balance"
+	fallbackLast    = "last"
+)
+
+func isValidFallback(fallback string) bool {
+	switch fallback {
+	case fallbackFirst, fallbackBalance, fallbackLast:
+		return true
+	}
+	return false
+}
+
+// resolveFallback turns an --unknown-fallback value into the mode toggle
+// should move to from an unrecognized mode ("" for the first in the cycle)
+func resolveFallback(fallback string) modes.PowerMode {
+	switch fallback {
+	case fallbackBalance:
+		return modes.Balance
+	case fallbackLast:
+		st, err := state.Load(state.DefaultPath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return modes.PowerMode(st.LastMode)
+	}
+	return ""
+}
+
+// recordLastMode remembers the mode the helper just set, for
+// --unknown-fallback=last, and when, for --debounce
+func recordLastMode(mode string) {
+	path := state.DefaultPath()
+	st, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	st.LastMode = mode
+	st.LastModeAt = time.Now()
+	st.LastChange = st.LastModeAt
+	if err := st.Save(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// --toast-source values
+const (
+	toastSourceAll      = "all"
+	toastSourceExternal = "external"
+	toastSourceSelf     = "self"
+)
+
+func isValidToastSource(source string) bool {
+	switch source {
+	case toastSourceAll, toastSourceExternal, toastSourceSelf:
+		return true
+	}
+	return false
+}
+
+// selfChangeSlack is added to the watch interval when deciding whether a
+// detected change is one the helper made, to cover slow llt.exe calls
+const selfChangeSlack = 5 * time.Second
+
+// changedBySelf reports whether the helper set mode within window, going by
+// the mode and time recorded by recordLastMode
+func changedBySelf(mode string, window time.Duration) bool {
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return st.LastMode == mode && time.Since(st.LastModeAt) <= window
+}
@@ -0,0 +1,47 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handleHUD shows a persistent OSD with the current mode and repaints it
+// whenever the mode changes, polling like watch until the process exits
+func handleHUD(client *llt.Client, manager *modes.Manager, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		return err
+	}
+
+	hud, err := toast.NewHUD("Power Mode", manager.GetModeMetadata(modes.PowerMode(current)).Name)
+	if err != nil {
+		return err
+	}
+	defer hud.Close()
+	onShutdown(hud.Close)
+
+	for {
+		time.Sleep(interval)
+
+		mode, err := client.GetCurrentMode()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		if mode != current {
+			current = mode
+			hud.Update("Power Mode", manager.GetModeMetadata(modes.PowerMode(current)).Name)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/app"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+)
+
+// --order values for list --available-only
+const (
+	orderLLT       = "llt"       // the order LLT reports the modes in
+	orderCanonical = "canonical" // the cycle, then configured custom modes
+)
+
+func isValidOrder(order string) bool {
+	return order == orderLLT || order == orderCanonical
+}
+
+// listEntry is one mode in list --json: its status fields plus whether it
+// is the active mode
+type listEntry struct {
+	statusResult
+	Current bool `json:"current"`
+}
+
+// handleList prints the known modes (the cycle plus configured custom
+// modes), marking the active one. With availableOnly, modes the device
+// doesn't offer are left out, and order picks between LLT's order and the
+// known modes' order.
+func handleList(client *llt.Client, manager *modes.Manager, availableOnly bool, order string, jsonOut bool) error {
+	results, err := listModes(client, manager, availableOnly, order)
+	if err != nil {
+		return err
+	}
+
+	if jsonOut {
+		data, err := json.Marshal(results)
+		if err != nil {
+			return fmt.Errorf("failed to encode modes: %w", err)
+		}
+		printOut(string(data) + "\n")
+		return nil
+	}
+
+	var b strings.Builder
+	for _, r := range results {
+		marker := " "
+		if r.Current {
+			marker = "*"
+		}
+		fmt.Fprintf(&b, "%s %-12s %s\n", marker, r.Mode, r.Name)
+	}
+	printOut(b.String())
+	return nil
+}
+
+// listModes returns the entries handleList prints, for list and serve
+func listModes(client *llt.Client, manager *modes.Manager, availableOnly bool, order string) ([]listEntry, error) {
+	known := manager.Modes()
+
+	if availableOnly {
+		available, err := client.ListAvailableModes()
+		if err != nil {
+			return nil, err
+		}
+		known = availableModes(known, available, order)
+	}
+
+	// The list is still useful without the marker, e.g. while LLT is busy
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: can't mark the current mode: %v\n", err)
+	}
+
+	results := make([]listEntry, 0, len(known))
+	for _, mode := range known {
+		results = append(results, listEntry{
+			statusResult: app.Describe(manager, string(mode)),
+			Current:      err == nil && string(mode) == current,
+		})
+	}
+	return results, nil
+}
+
+// availableModes keeps the known modes that LLT lists as available, in
+// LLT's order or, for orderCanonical, in the order of known. LLT's names are
+// already canonical (localized names translated), so only case differences
+// between config ids and LLT output remain to be ignored.
+func availableModes(known []modes.PowerMode, available []string, order string) []modes.PowerMode {
+	var kept []modes.PowerMode
+	if order == orderCanonical {
+		for _, mode := range known {
+			if slices.ContainsFunc(available, func(name string) bool { return strings.EqualFold(string(mode), strings.TrimSpace(name)) }) {
+				kept = append(kept, mode)
+			}
+		}
+		return kept
+	}
+
+	for _, name := range available {
+		i := slices.IndexFunc(known, func(mode modes.PowerMode) bool { return strings.EqualFold(string(mode), strings.TrimSpace(name)) })
+		if i >= 0 && !slices.Contains(kept, known[i]) {
+			kept = append(kept, known[i])
+		}
+	}
+	return kept
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package main
+
+import "strings"
+
+// stringList is a flag that may be given more than once, collecting each value
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
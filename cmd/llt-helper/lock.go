@@ -0,0 +1,77 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/state"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// lockedMode returns the mode pinned by `lock`, or "" when unlocked
+func lockedMode() string {
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return st.LockedMode
+}
+
+// handleLock sets a mode and pins it: toggle/set refuse to change it (unless
+// --force) and watch --enforce re-applies it, until `unlock` is run
+func handleLock(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, mode string) error {
+	if mode == "" {
+		return fmt.Errorf("usage: lock --mode=MODE")
+	}
+	if !manager.IsValidMode(mode) {
+		return fmt.Errorf("unknown power mode: %s", mode)
+	}
+
+	if err := setModeVerified(client, manager, mode); err != nil {
+		return err
+	}
+
+	path := state.DefaultPath()
+	st, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	st.LockedMode = mode
+	if err := st.Save(path); err != nil {
+		return err
+	}
+
+	meta := manager.GetModeMetadata(modes.PowerMode(mode))
+	printOut(fmt.Sprintf("Locked to %s\n", meta.Name))
+	if err := notifier.ShowModeChange(modeChangeMessage(meta, meta.Name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+	}
+
+	return nil
+}
+
+// handleUnlock removes the lock set by `lock`
+func handleUnlock() error {
+	path := state.DefaultPath()
+	st, err := state.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if st.LockedMode == "" {
+		printOut("Not locked\n")
+		return nil
+	}
+
+	st.LockedMode = ""
+	if err := st.Save(path); err != nil {
+		return err
+	}
+
+	printOut("Unlocked\n")
+	return nil
+}
@@ -1,12 +1,21 @@
+//go:build windows
+
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 	"unsafe"
 
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/app"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
 	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
 	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
 	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
@@ -59,16 +68,56 @@ func writeToConsole(message string) {
 	)
 }
 
+// consoleDisabled reports whether console attachment was turned off via
+// --no-console (which can appear anywhere and is removed from os.Args) or the
+// LLT_HELPER_NO_CONSOLE environment variable
+func consoleDisabled() bool {
+	disabled := false
+	if v := os.Getenv("LLT_HELPER_NO_CONSOLE"); v != "" && v != "0" {
+		disabled = true
+		noConsoleSource = sourceEnv
+	}
+
+	args := os.Args[:1]
+	for _, arg := range os.Args[1:] {
+		if arg == "--no-console" || arg == "-no-console" {
+			disabled = true
+			noConsoleSource = sourceFlag
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+
+	return disabled
+}
+
+// printOut writes a message to the attached console and stdout
+func printOut(message string) {
+	writeToConsole(message)
+	fmt.Print(message)
+}
+
 func main() {
-	// Attempt to attach to parent console for CLI output
-	attachConsole()
+	// Attempt to attach to parent console for CLI output, unless the launcher
+	// asked us not to (attaching can cause focus/flash side effects) or safe
+	// mode keeps the helper away from windows altogether
+	noConsole := consoleDisabled()
+	safeMode := safeModeEnabled()
+	if !noConsole && !safeMode {
+		attachConsole()
+	}
+
+	configPath, err := configPathFromArgs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
 
 	// Check for global flags first
 	if len(os.Args) > 1 {
 		if os.Args[1] == "--version" || os.Args[1] == "-version" {
-			versionMsg := fmt.Sprintf("llt-helper version %s\n", version)
-			writeToConsole(versionMsg)
-			fmt.Print(versionMsg)
+			printVersion(slices.Contains(os.Args[2:], "--json") || slices.Contains(os.Args[2:], "-json"))
 			os.Exit(0)
 		}
 		if os.Args[1] == "--help" || os.Args[1] == "-help" || os.Args[1] == "-h" {
@@ -77,185 +126,1116 @@ func main() {
 		}
 	}
 
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v (using defaults)\n", err)
+	}
+
+	// A bare invocation (e.g. double-clicking the exe) runs the configured
+	// default command, if any
 	if len(os.Args) < 2 {
-		printUsage()
-		os.Exit(1) // Exit code 1 for missing arguments
+		defaultArgs, _ := defaultCommand(cfg)
+		if len(defaultArgs) == 0 {
+			printUsage()
+			os.Exit(exitUsage)
+		}
+		os.Args = append(os.Args[:1], defaultArgs...)
 	}
 
+	// Expand aliases (e.g. "perf" -> "set --mode=performance") before flag parsing
+	args, err := resolveAlias(os.Args[1:], cfg.Aliases)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	os.Args = append(os.Args[:1], args...)
+
 	command := os.Args[1]
 
 	// Parse command-specific flags
 	var modeFlag string
 	var noToast bool
 	var modesFlag string
+	var graph bool
+	var reverse bool
+	var modesAC, modesBattery string
 	var helpFlag bool
+	var toastMultiline bool
+	var toastAnimation string
+	var watchOpts watchOptions
+	var jsonOut bool
+	var statusOpts statusOptions
+	var statusFormat string
+	var writePath, writeFormat string
+	var verbose bool
+	var readSource string
+	var modeMapFlag string
+	var debounce time.Duration
+	var iconTheme string
+	var timeout time.Duration
+	var maxRate float64
+	var retries int
+	var retryDelay time.Duration
+	var unknownFallback string
+	var toastDelay time.Duration
+	var toastDuration time.Duration
+	var toastTextShadow bool
+	var toastNoTopmost bool
+	var toastMonitor int
+	var toastShowBattery bool
+	var toastScale float64
+	var toastTheme string
+	var toastProgress bool
+	var toastMaxWidth int
+	var singleInstance string
+	var elevateOpts elevateOptions
+	var identify bool
+	var onlyOn string
+	var toggleFlag bool
+	var lltArgs stringList
+	var availableOnly bool
+	var listOrder string
+	var toastWait bool
+	var toastStack bool
+	var toastPosition string
+	var toastCooldown time.Duration
+	var showConfig bool
+	var force bool
+	var exitOnNoop bool
+	var autoOpts autoOptions
+	var watchFeatures string
+	var etwEnabled bool
+	var revertTo string
+	var revertAfter time.Duration
+	var confirmOpts confirmOptions
+	var benchOpts benchmarkOptions
+	var modeIndex int
+	var cycleIndex int
+	var osdTitle, osdMessage, osdIconPath, osdColor string
+	var waitForLLT time.Duration
+	var lltPath string
+	var logFile string
 
 	fs := flag.NewFlagSet(command, flag.ExitOnError)
-	fs.StringVar(&modeFlag, "mode", "", "Target mode for set command (quiet|balance|performance)")
-	fs.BoolVar(&noToast, "no-toast", false, "Suppress toast notification")
-	fs.StringVar(&modesFlag, "modes", "", "Comma-separated list of modes to cycle through for toggle command (e.g., quiet,performance)")
+	fs.StringVar(&modeFlag, "mode", "", "Target mode for set command (quiet|balance|performance, or - to read it from stdin)")
+	fs.IntVar(&modeIndex, "mode-index", 0, "Target mode for set command by LLT index (1|2|3|255)")
+	fs.IntVar(&cycleIndex, "index", 0, "Target mode for set command by 1-based position in the cycle")
+	fs.BoolVar(&noToast, "no-toast", configToastOff(cfg), "Suppress toast notification (default from \"toast\" in the config file)")
+	fs.StringVar(&modesFlag, "modes", "", "Comma-separated list of modes to cycle through for toggle command, or to preview with modes (e.g., quiet,performance)")
+	fs.StringVar(&modesAC, "modes-ac", "", "Modes for toggle/next to cycle on AC power (default from sequenceAC in the config file)")
+	fs.StringVar(&modesBattery, "modes-battery", "", "Modes for toggle/next to cycle on battery (default from sequenceBattery in the config file)")
+	fs.BoolVar(&reverse, "reverse", false, "Cycle backwards to the previous mode (toggle)")
+	fs.BoolVar(&graph, "graph", false, "Print the cycle on one line, e.g. quiet → balance → performance (modes)")
+	fs.BoolVar(&toastMultiline, "toast-multiline", false, "Word-wrap long toast messages instead of clipping them")
+	fs.StringVar(&toastAnimation, "toast-animation", toast.AnimationNone, "Toast animation (none|fade|slide)")
+	fs.DurationVar(&toastDelay, "toast-delay", 0, "Wait this long before showing the toast")
+	fs.DurationVar(&toastDuration, "toast-duration", configDuration(cfg), "How long the toast stays up (default from osdDurationMs in the config file, else 3s)")
+	fs.StringVar(&toastPosition, "toast-position", toast.PositionBottomCenter, "Where the toast appears (e.g. bottom-center, top-right)")
+	fs.DurationVar(&toastCooldown, "toast-cooldown", 0, "Skip toasts shown less than this long after the previous one")
+	fs.StringVar(&osdTitle, "title", "Power Mode Changed", "Title of the sample toast (test-osd)")
+	fs.StringVar(&osdMessage, "message", "Switched to Balance Mode", "Message of the sample toast (test-osd)")
+	fs.StringVar(&osdIconPath, "osd-icon", "", "Icon drawn on the left of the sample toast (test-osd)")
+	fs.StringVar(&osdColor, "osd-color", "", "Accent color (#RRGGBB) along the top of the sample toast (test-osd)")
+	fs.BoolVar(&toastTextShadow, "toast-text-shadow", false, "Draw a drop shadow under the toast text")
+	fs.BoolVar(&toastWait, "toast-wait", configToastWait(cfg), "Wait for the toast to close before exiting; false shows it from a detached process (default from \"toastWait\" in the config file)")
+	fs.BoolFunc("osd-async", "Same as --toast-wait=false", func(value string) error {
+		async, err := strconv.ParseBool(value)
+		toastWait = !async
+		return err
+	})
+	fs.BoolVar(&toastStack, "toast-stack", false, "Stack the toast with other visible helper toasts instead of overlapping them")
+	fs.BoolVar(&toastNoTopmost, "toast-no-topmost", false, "Don't keep the toast above all other windows")
+	fs.IntVar(&toastMonitor, "toast-monitor", 0, "Monitor to show the toast on, as numbered by the monitors command (0 = the one with the active window)")
+	fs.BoolVar(&toastShowBattery, "toast-show-battery", false, "Add the battery charge level to mode change toasts")
+	fs.Float64Var(&toastScale, "toast-scale", 1, "Multiply the toast's size and fonts by this factor (0.5-3.0)")
+	fs.StringVar(&toastTheme, "toast-theme", toast.ThemeDark, "Toast colors: dark, light or auto (follow the Windows app theme)")
+	fs.IntVar(&toastMaxWidth, "toast-max-width", toast.DefaultMaxWidth, "Widest the toast may grow to fit long text, in pixels (400 keeps it fixed)")
+	fs.BoolVar(&toastProgress, "toast-progress", false, "Draw a bar counting down until the toast closes; preset also shows its progress")
+	fs.StringVar(&onlyOn, "only-on", "", "Only change the mode on this power source: battery or ac (toggle, set)")
+	fs.Var(&lltArgs, "llt-arg", "Advanced/unsafe: extra argument appended to llt.exe get/set calls (repeatable)")
+	fs.BoolVar(&availableOnly, "available-only", false, "Only list modes this device supports (list)")
+	fs.StringVar(&listOrder, "order", orderLLT, "Order of list --available-only: llt (as LLT reports them) or canonical (the cycle's)")
+	fs.BoolVar(&toggleFlag, "toggle", false, "Advance to the next level (backlight, refresh-rate)")
+	fs.BoolVar(&identify, "identify", false, "Flash each monitor's number on it (monitors)")
+	fs.DurationVar(&watchOpts.interval, "interval", 2*time.Second, "Polling interval for watch command")
+	fs.StringVar(&watchOpts.enforce, "enforce", "", "Mode that watch re-applies whenever it drifts")
+	fs.DurationVar(&watchOpts.cooldown, "cooldown", 10*time.Second, "Minimum time between watch --enforce corrections")
+	fs.BoolVar(&watchOpts.toastOnEnforce, "toast-on-enforce", false, "Show a toast for each watch --enforce correction")
+	fs.BoolVar(&watchOpts.toastOnChange, "toast-on-change", false, "Show a toast for each mode change watch detects")
+	fs.StringVar(&watchOpts.toastSource, "toast-source", toastSourceAll, "Which detected changes get a toast: all, external or self (watch)")
+	fs.DurationVar(&watchOpts.errorSummary, "error-summary-interval", 0, "Show one toast per interval summarizing LLT errors (watch, serve)")
+	fs.BoolVar(&watchOpts.schedule, "schedule", false, "Apply the config file's schedule rules while watching")
+	fs.StringVar(&watchFeatures, "features", "", "Comma-separated LLT features watch reports changes of as JSON, e.g. power-mode,battery")
+	fs.BoolVar(&jsonOut, "json", false, "Output machine-readable JSON (status, watch, doctor, sensors, config, whereis)")
+	fs.BoolVar(&statusOpts.short, "short", false, "Print only the current mode's symbol (status)")
+	fs.BoolVar(&statusOpts.icon, "icon", false, "Print only the current mode's icon path, or an empty line if it has none (status)")
+	fs.BoolVar(&statusOpts.timestamp.enabled, "with-timestamp", false, "Include when the mode was read, in ISO 8601 (status, watch)")
+	fs.BoolVar(&statusOpts.timestamp.local, "local-time", false, "Use local time instead of UTC for --with-timestamp")
+	fs.BoolVar(&statusOpts.strict, "strict", false, "Fail if the current mode isn't a known one (status)")
+	fs.BoolVar(&statusOpts.toast, "toast", false, "Also show the current mode as a toast (status)")
+	fs.StringVar(&statusFormat, "format", "", "Go template for the status output, e.g. '{{.Name}} ({{.Mode}})'")
+	fs.StringVar(&writePath, "write", "", "File to write the current mode to (status, watch keeps it updated)")
+	fs.StringVar(&writeFormat, "write-format", defaultWriteFormat, "Go template for the --write file")
+	fs.BoolVar(&verbose, "verbose", false, "Print each llt.exe invocation and how long it took")
+	fs.StringVar(&readSource, "read-source", llt.ReadSourceCLI, "Where to read the current mode from (cli|wmi|auto)")
+	fs.StringVar(&modeMapFlag, "mode-map", "", "Translate LLT mode names to mode ids, e.g. Turbo=performance,Eco=quiet (adds to modeMap in the config file)")
+	fs.DurationVar(&debounce, "debounce", 0, "Skip toggle/set if the mode was changed less than this long ago")
+	fs.StringVar(&iconTheme, "icon-theme", "", "Icon set to use from assets/icons/<name>/")
+	fs.DurationVar(&waitForLLT, "wait-for-llt", 0, "Keep retrying this long for LLT to start before giving up")
+	fs.StringVar(&lltPath, "llt-path", "", "Path to llt.exe, overriding LLT_PATH and the usual install locations")
+	fs.DurationVar(&timeout, "timeout", llt.DefaultTimeout, "How long to wait for each llt.exe call")
+	fs.Float64Var(&maxRate, "max-rate", 0, "Start at most this many llt.exe processes per second (0 = no limit)")
+	fs.IntVar(&retries, "retries", llt.DefaultRetries, "How many times to retry a failed llt.exe call (0 = no retries)")
+	fs.DurationVar(&retryDelay, "retry-delay", llt.DefaultRetryDelay, "Wait before the first retry, doubling after each one")
+	fs.StringVar(&unknownFallback, "unknown-fallback", fallbackFirst, "Where toggle goes from an unrecognized mode (first|balance|last)")
+	fs.BoolVar(&confirmOpts.confirm, "confirm", false, "Ask before switching to GodMode/custom modes (toggle, set)")
+	fs.BoolVar(&confirmOpts.yes, "yes", false, "Answer yes to --confirm and reset-state (required when no console is attached)")
+	fs.IntVar(&benchOpts.count, "count", 10, "Number of get/set cycles (benchmark)")
+	fs.BoolVar(&benchOpts.dryRun, "dry-run", false, "Skip the set in each cycle (benchmark)")
+	fs.StringVar(&singleInstance, "single-instance", singleInstanceWait, "When another helper is changing settings: wait, fail or allow")
+	fs.BoolVar(&elevateOpts.elevate, "elevate", false, "Relaunch as administrator (UAC prompt) when an operation requires it")
+	fs.BoolVar(&elevateOpts.elevated, strings.TrimPrefix(elevatedFlag, "--"), false, "Internal: set on the process started by --elevate")
+	fs.BoolVar(&broadcastChanges, "broadcast", false, "Signal other programs after each mode change (see README)")
+	fs.BoolVar(&force, "force", false, "Change the mode even while it's locked (toggle, set); set also re-applies the current mode")
+	fs.StringVar(&revertTo, "revert-to", "", "Mode set switches back to after --revert-after")
+	fs.DurationVar(&revertAfter, "revert-after", 0, "How long set keeps the mode before switching to --revert-to")
+	fs.BoolVar(&exitOnNoop, "exit-on-noop", false, "Exit with code 7 when set (or auto --apply) finds the mode already active and changes nothing")
+	fs.StringVar(&autoOpts.onAC, "on-ac", "", "Mode for auto to use on AC power")
+	fs.StringVar(&autoOpts.onBattery, "on-battery", "", "Mode for auto to use on battery")
+	fs.BoolVar(&autoOpts.apply, "apply", false, "Set the mode auto picks instead of only printing it")
+	fs.BoolVar(&showConfig, "show", false, "Print the effective configuration (config)")
+	fs.BoolVar(&etwEnabled, "etw", false, "Write diagnostic events to the LLTHelper ETW provider")
+	fs.StringVar(&logFile, "log-file", "", "Append timestamped troubleshooting entries to this file (default from LLT_HELPER_LOG)")
 	fs.BoolVar(&helpFlag, "help", false, "Show help message")
 	fs.BoolVar(&helpFlag, "h", false, "Show help message (shorthand)")
 
 	fs.Usage = func() {
-		printUsage()
+		printCommandUsage(fs, command)
 	}
 
 	// Parse flags after the command
 	if len(os.Args) > 2 {
 		if err := fs.Parse(os.Args[2:]); err != nil {
 			// flag.ExitOnError handles exit usually, but if we catch it:
-			os.Exit(2)
+			os.Exit(exitUsage)
 		}
 	}
 
 	if helpFlag {
-		printUsage()
+		printCommandUsage(fs, command)
+		os.Exit(0)
+	}
+
+	// Through the environment, --llt-path reaches every client this process
+	// creates and the helper processes it starts
+	if lltPath != "" {
+		os.Setenv(llt.PathEnv, lltPath)
+	}
+
+	// Safe mode never creates a window; output goes to stdout/stderr only
+	if safeMode {
+		noToast = true
+		if windowCommands[command] || (command == "monitors" && identify) {
+			fmt.Fprintf(os.Stderr, "Error: %s needs a window, which safe mode rules out (see --safe-mode)\n", command)
+			os.Exit(exitUsage)
+		}
+	}
+
+	tracer := newETWTracer(etwEnabled, command, os.Args[2:])
+	fileLog = newFileLogger(logFile, verbose, command, os.Args[2:])
+
+	if !llt.IsValidReadSource(readSource) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --read-source '%s' (use cli, wmi or auto)\n", readSource)
+		os.Exit(exitUsage)
+	}
+
+	if toastDelay < 0 || toastDelay > toast.MaxDelay {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-delay '%s' (must be between 0 and %s)\n", toastDelay, toast.MaxDelay)
+		os.Exit(exitUsage)
+	}
+
+	if toastDuration < toast.MinDuration || toastDuration > toast.MaxDuration {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-duration '%s' (must be between %s and %s)\n", toastDuration, toast.MinDuration, toast.MaxDuration)
+		os.Exit(exitUsage)
+	}
+
+	if (revertTo != "") != (revertAfter > 0) || revertAfter < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --revert-to and --revert-after (a positive duration) go together\n")
+		os.Exit(exitUsage)
+	}
+
+	if maxRate < 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid --max-rate %g (must not be negative)\n", maxRate)
+		os.Exit(exitUsage)
+	}
+
+	if retries < 0 || retryDelay < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --retries and --retry-delay must not be negative\n")
+		os.Exit(exitUsage)
+	}
+
+	if !isValidOrder(listOrder) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --order '%s' (use llt or canonical)\n", listOrder)
+		os.Exit(exitUsage)
+	}
+
+	if !isValidFallback(unknownFallback) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --unknown-fallback '%s' (use first, balance or last)\n", unknownFallback)
+		os.Exit(exitUsage)
+	}
+
+	if !modes.IsValidIconTheme(iconTheme) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --icon-theme '%s' (use the name of a folder in assets/icons)\n", iconTheme)
+		os.Exit(exitUsage)
+	}
+
+	if !toast.IsValidPosition(toastPosition) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-position '%s' (use top-left, top-center, top-right, center, bottom-left, bottom-center or bottom-right)\n", toastPosition)
+		os.Exit(exitUsage)
+	}
+
+	if !toast.IsValidAnimation(toastAnimation) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-animation '%s' (use none, fade or slide)\n", toastAnimation)
+		os.Exit(exitUsage)
+	}
+
+	if !isValidOnlyOn(onlyOn) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --only-on '%s' (use battery or ac)\n", onlyOn)
+		os.Exit(exitUsage)
+	}
+
+	if watchOpts.features, err = parseWatchFeatures(watchFeatures); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --features: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	if !isValidToastSource(watchOpts.toastSource) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-source '%s' (use all, external or self)\n", watchOpts.toastSource)
+		os.Exit(exitUsage)
+	}
+
+	if toastMonitor < 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-monitor %d (use 0 for the active window's monitor or a number from the monitors command)\n", toastMonitor)
+		os.Exit(exitUsage)
+	}
+
+	if !isValidSingleInstance(singleInstance) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --single-instance '%s' (use wait, fail or allow)\n", singleInstance)
+		os.Exit(exitUsage)
+	}
+
+	if toastScale < toast.MinScale || toastScale > toast.MaxScale {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-scale %g (must be between %g and %g)\n", toastScale, toast.MinScale, toast.MaxScale)
+		os.Exit(exitUsage)
+	}
+
+	if toastMaxWidth < toast.MinWidth || toastMaxWidth > toast.MaxWidthLimit {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-max-width %d (must be between %d and %d)\n", toastMaxWidth, toast.MinWidth, toast.MaxWidthLimit)
+		os.Exit(exitUsage)
+	}
+
+	if !toast.IsValidTheme(toastTheme) {
+		fmt.Fprintf(os.Stderr, "Error: invalid --toast-theme %q (must be dark, light or auto)\n", toastTheme)
+		os.Exit(exitUsage)
+	}
+
+	if statusFormat != "" {
+		tmpl, err := parseStatusFormat(statusFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --format: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		statusOpts.format = tmpl
+	}
+
+	if writePath != "" {
+		tmpl, err := parseStatusFormat(writeFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --write-format: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		statusOpts.write = statusFile{path: writePath, format: tmpl}
+		watchOpts.write = statusOpts.write
+	}
+	watchOpts.timestamp = statusOpts.timestamp
+
+	// Serialize helpers started together (e.g. two buttons pressed at once)
+	// so they don't race on llt.exe and the state file
+	if singleInstanceCommands[command] {
+		if err := acquireInstance(singleInstance); errors.Is(err, errAnotherInstance) {
+			fmt.Fprintf(os.Stderr, "Error: %v (see --single-instance)\n", err)
+			os.Exit(exitAnotherInstance)
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	changesMode := command == "toggle" || command == "next" || command == "set" || (command == "auto" && autoOpts.apply)
+
+	// A mode pinned with `lock` can only be changed with --force
+	if changesMode && !force {
+		if locked := lockedMode(); locked != "" {
+			fmt.Fprintf(os.Stderr, "Error: power mode is locked to %s (run unlock, or pass --force)\n", locked)
+			os.Exit(exitLocked)
+		}
+	}
+
+	// Coalesce rapid repeat presses (e.g. key bounce) into a single change
+	if changesMode && debounced(debounce) {
+		printOut("Ignored: mode changed moments ago (--debounce)\n")
+		os.Exit(0)
+	}
+
+	if command == "unlock" {
+		if err := handleUnlock(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		os.Exit(0)
+	}
+
+	// reset-state only touches the helper's own files
+	if command == "reset-state" {
+		if err := handleResetState(confirmOpts.yes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		os.Exit(0)
+	}
+
+	// whereis diagnoses LLT not being found, so it runs without a client
+	if command == "whereis" {
+		if err := handleWhereis(jsonOut); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		os.Exit(0)
+	}
+
+	// config only handles settings, so it doesn't need LLT
+	if command == "config" {
+		if err := handleConfig(fs, cfg, configPath, showConfig, jsonOut, fs.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
 		os.Exit(0)
 	}
 
 	// Initialize components
-	lltClient, err := llt.NewClient()
+	modeManager, configCycle := newModeManager(cfg)
+	modeManager.SetIconTheme(iconTheme)
+	modeManager.SetUnknownFallback(resolveFallback(unknownFallback))
+
+	modeMap, err := resolveModeMap(cfg, modeMapFlag)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
+	warnUnknownMappedModes(modeManager, modeMap)
 
-	if !lltClient.IsRunning() {
-		fmt.Fprintf(os.Stderr, "Error: LLT not running or CLI disabled\n")
-		os.Exit(1)
+	osd := toast.NewNotifier()
+	osd.Multiline = toastMultiline
+	osd.Animation = toastAnimation
+	osd.Delay = toastDelay
+	osd.Duration = toastDuration
+	osd.TextShadow = toastTextShadow
+	osd.NoTopmost = toastNoTopmost
+	osd.Monitor = toastMonitor
+	osd.Scale = toastScale
+	osd.Theme = toastTheme
+	osd.Progress = toastProgress
+	osd.MaxWidth = toastMaxWidth
+	osd.Stack = toastStack
+	osd.Position = toastPosition
+	var notifier toast.Notifier = toast.NopNotifier{}
+	if !noToast {
+		var shown toast.Notifier = osd
+		if !toastWait {
+			shown = newDetachedNotifier(fs)
+		}
+		notifier = shown
+		if toastCooldown > 0 {
+			notifier = cooldownNotifier{Notifier: shown, cooldown: toastCooldown}
+		}
 	}
 
-	modeManager := modes.NewManager()
-	var notifier *toast.Notifier
-	if !noToast {
-		notifier = toast.NewNotifier()
+	// test-osd previews the toast settings without touching LLT
+	if command == "test-osd" {
+		if err := osd.ShowIcon(osdTitle, osdMessage, osdIconPath, osdColor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		os.Exit(0)
+	}
+
+	// Context-aware buttons: do nothing unless on the requested power source
+	if command == "toggle" || command == "next" || command == "set" {
+		source, err := onlyOnMismatch(onlyOn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; ignoring --only-on\n", err)
+		} else if source != "" {
+			msg := fmt.Sprintf("Ignored: on %s (--only-on=%s)", powerSourceName(source), onlyOn)
+			printOut(msg + "\n")
+			if !noToast {
+				if err := osd.Show("Power Mode Unchanged", msg); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+				}
+			}
+			os.Exit(0)
+		}
+	}
+
+	if command == "monitors" {
+		if err := handleMonitors(osd, jsonOut, identify); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		os.Exit(0)
+	}
+
+	var lltClient *llt.Client
+	if waitForLLT > 0 {
+		lltClient, err = llt.NewClientWait(waitForLLT)
+	} else {
+		lltClient, err = llt.NewClient()
+	}
+	fileLog.client(lltClient, err)
+	if err == nil {
+		lltClient.ReadSource = readSource
+		lltClient.Timeout = timeout
+		lltClient.MaxRate = maxRate
+		lltClient.Retries = retries
+		if retries == 0 {
+			lltClient.Retries = -1
+		}
+		lltClient.RetryDelay = retryDelay
+		lltClient.ExtraArgs = lltArgs
+		lltClient.ModeMap = modeMap
+		// One-shot commands read the mode at most once; the polling ones
+		// (and benchmark, which times the reads) need every read fresh
+		switch command {
+		case "watch", "serve", "hud", "tray", "benchmark", revertCommand:
+		default:
+			lltClient.CacheMode = true
+		}
+		if verbose || tracer != nil || fileLog != nil {
+			lltClient.Trace = func(call llt.Call) {
+				if verbose {
+					traceCall(call)
+				}
+				tracer.call(call)
+				fileLog.call(lltClient.Path(), call)
+			}
+		}
+		if toastShowBattery && !noToast {
+			notifier = batteryNotifier{Notifier: notifier, client: lltClient}
+		}
+	}
+	if fileLog != nil && !noToast {
+		notifier = loggedNotifier{Notifier: notifier}
+	}
+
+	// doctor reports LLT problems rather than failing on them
+	if command == "doctor" {
+		if err := handleDoctor(lltClient, err, modeManager, jsonOut); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		os.Exit(0)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		showFailure(notifier, command, err)
+		code := exitCode(err)
+		if code == exitFailed {
+			// e.g. LLT_PATH naming a directory: there's no llt.exe to run
+			code = exitLLTNotFound
+		}
+		os.Exit(code)
+	}
+
+	// debug only describes llt.exe calls, so the CLI needn't respond.
+	// Hidden: a troubleshooting tool, not listed in the usage text
+	if command == "debug" {
+		if err := handleDebug(lltClient, fs.Args(), jsonOut); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		os.Exit(0)
+	}
+
+	// enable-cli exists to fix a CLI that isn't responding yet
+	if command == "enable-cli" {
+		if err := handleEnableCLI(lltClient); err != nil {
+			maybeElevate(err, elevateOpts)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitFailed)
+		}
+		os.Exit(0)
+	}
+
+	if err := lltClient.Probe(); err != nil {
+		// The raw cause (a sharing violation on llt.exe) says nothing useful
+		if errors.Is(err, llt.ErrLLTBusyUpdating) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", llt.ErrLLTBusyUpdating)
+			showFailure(notifier, command, err)
+			os.Exit(exitLLTUpdating)
+		}
+		maybeElevate(err, elevateOpts)
+		if errors.Is(err, llt.ErrElevationRequired) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			showFailure(notifier, command, err)
+			os.Exit(exitLLTBlocked)
+		}
+		// A blocked llt.exe needs fixing on the user's side; the WMI
+		// fallback below would only hide that
+		if errors.Is(err, llt.ErrLLTNotExecutable) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			showFailure(notifier, command, err)
+			os.Exit(exitLLTBlocked)
+		}
+		// watch keeps polling until LLT is back, and status can still be
+		// answered from WMI when the CLI is unavailable
+		switch {
+		case command == "watch":
+			fmt.Fprintf(os.Stderr, "Warning: LLT not running or CLI disabled, waiting for it\n")
+		case command != "status" || readSource == llt.ReadSourceCLI:
+			fmt.Fprintf(os.Stderr, "Error: LLT not running or CLI disabled\n")
+			fileLog.error("LLT not running or CLI disabled: %v", err)
+			showFailure(notifier, command, errors.New("LLT not running or CLI disabled"))
+			os.Exit(exitLLTNotRunning)
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: LLT not running or CLI disabled, reading mode via WMI\n")
+		}
+	}
+
+	// A cycle for the current power source applies when --modes isn't given
+	if (command == "toggle" || command == "next") && modesFlag == "" {
+		if modesFlag, err = powerSourceCycle(modeManager, cfg, modesAC, modesBattery); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitUsage)
+		}
+	}
+
+	// Unless the config file sets a sequence, the default cycle follows the
+	// modes this device offers. Listing them costs an llt.exe call, so only
+	// the commands that cycle through (or check against) the default
+	// sequence ask; the rest keep the built-in one
+	usesCycle := (command == "toggle" || command == "modes" || command == "tray" || command == "serve") && modesFlag == "" ||
+		command == "set" && cycleIndex != 0
+	if !configCycle && (usesCycle || (command == "status" && statusOpts.strict)) {
+		available, err := lltClient.ListAvailableModes()
+		if err != nil && !errors.Is(err, llt.ErrFeatureUnsupported) {
+			fmt.Fprintf(os.Stderr, "Warning: can't list available modes, using the default cycle: %v\n", err)
+		}
+		modeManager.SetSequence(available)
+	}
+
+	// Long-running commands release the pipe and close their windows when stopped
+	switch command {
+	case "watch", "serve", "hud", "tray":
+		handleShutdown()
+	}
+
+	// Apply a set --revert-after whose waiting process was killed
+	if command != revertCommand {
+		if err := completeOverdueRevert(lltClient, modeManager, notifier); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 	}
 
 	switch command {
 	case "toggle":
-		err = handleToggle(lltClient, modeManager, notifier, modesFlag)
+		err = handleToggle(lltClient, modeManager, notifier, modesFlag, reverse, confirmOpts)
+	case "next":
+		err = handleNext(lltClient, modeManager, notifier, modesFlag, confirmOpts)
 	case "set":
+		if cycleIndex != 0 && (modeFlag != "" || modeIndex != 0) {
+			fmt.Fprintf(os.Stderr, "Error: use only one of --mode, --mode-index and --index\n")
+			os.Exit(exitUsage)
+		}
+		if modeIndex != 0 {
+			if modeFlag, err = lltClient.ModeForIndex(modeIndex); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitUsage)
+			}
+		}
+		if cycleIndex != 0 {
+			mode, err := modeManager.ModeAt(cycleIndex)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(exitUsage)
+			}
+			modeFlag = string(mode)
+		}
 		if modeFlag == "" {
 			fmt.Fprintf(os.Stderr, "Error: --mode flag required for set command\n")
 			printUsage() // Helpful to show usage on error
-			os.Exit(2)
+			os.Exit(exitUsage)
 		}
-		err = handleSet(lltClient, modeManager, modeFlag, notifier)
+		if revertTo != "" && !modeManager.IsValidMode(revertTo) {
+			fmt.Fprintf(os.Stderr, "Error: unknown power mode for --revert-to: %s\n", revertTo)
+			os.Exit(exitUnknownMode)
+		}
+		err = handleSet(lltClient, modeManager, modeFlag, notifier, confirmOpts, force)
+		// A burst is timed from now even if the mode was already active
+		if revertTo != "" && (err == nil || errors.Is(err, errAlreadyActive)) {
+			if revertErr := scheduleRevert(lltClient, revertTo, revertAfter); revertErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", revertErr)
+			}
+		}
+	case revertCommand:
+		// Hidden: started by set --revert-after, not listed in the usage text
+		err = handleRevertPending(lltClient, modeManager, notifier)
+	case "auto":
+		err = handleAuto(lltClient, modeManager, notifier, autoOpts, confirmOpts, force)
+	case "list":
+		err = handleList(lltClient, modeManager, availableOnly, listOrder, jsonOut)
+	case "modes":
+		err = handleModes(lltClient, modeManager, modesFlag, graph)
 	case "status":
-		err = handleStatus(lltClient, modeManager)
+		statusOpts.json = jsonOut
+		statusOpts.verbose = verbose
+		err = handleStatus(lltClient, modeManager, notifier, statusOpts)
+	case "benchmark":
+		// Hidden: a maintainer tool, not listed in the usage text
+		benchOpts.mode = modeFlag
+		benchOpts.json = jsonOut
+		err = handleBenchmark(lltClient, benchOpts)
+	case "schedule":
+		err = handleSchedule(lltClient, modeManager, notifier, cfg.Schedule)
+	case "watch":
+		watchOpts.rules = cfg.Schedule
+		watchOpts.json = jsonOut
+		err = handleWatch(lltClient, modeManager, notifier, watchOpts)
+	case "sensors":
+		err = handleSensors(lltClient, jsonOut)
+	case "backlight":
+		err = handleBacklight(lltClient, notifier, toggleFlag)
+	case "refresh-rate":
+		err = handleRefreshRate(lltClient, notifier, toggleFlag)
+	case "lock":
+		err = handleLock(lltClient, modeManager, notifier, modeFlag)
+	case "serve":
+		err = handleServe(lltClient, modeManager, notifier, modesFlag, watchOpts)
+	case "hud":
+		err = handleHUD(lltClient, modeManager, watchOpts.interval)
+	case "tray":
+		err = handleTray(lltClient, modeManager, notifier, modesFlag, watchOpts.interval)
+	case "profile":
+		err = handleProfile(lltClient, fs.Args())
+	case "feature":
+		err = handleFeature(lltClient, fs.Args())
+	case "preset":
+		err = handlePreset(lltClient, modeManager, notifier, cfg, configPath, fs.Args(), !toastWait, toastProgress && !noToast)
 	default:
 		fmt.Fprintf(os.Stderr, "Error: unknown command '%s'\n\n", command)
 		printUsage()
-		os.Exit(2)
+		os.Exit(exitUsage)
+	}
+
+	if verbose {
+		traceSpawns(command, lltClient.Calls())
+	}
+
+	// set (or auto) leaving an already active mode alone is success, unless the
+	// script asked to tell the two apart
+	if errors.Is(err, errAlreadyActive) {
+		if exitOnNoop {
+			tracer.stop(nil, exitNoop)
+			fileLog.stop(nil, exitNoop)
+			os.Exit(exitNoop)
+		}
+		err = nil
+	}
+
+	if errors.Is(err, llt.ErrLLTBusyUpdating) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", llt.ErrLLTBusyUpdating)
+		showFailure(notifier, command, err)
+		tracer.stop(err, exitLLTUpdating)
+		fileLog.stop(err, exitLLTUpdating)
+		os.Exit(exitLLTUpdating)
 	}
 
 	if err != nil {
+		maybeElevate(err, elevateOpts)
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(4)
+		showFailure(notifier, command, err)
+		code := exitCode(err)
+		tracer.stop(err, code)
+		fileLog.stop(err, code)
+		os.Exit(code)
+	}
+	tracer.stop(nil, exitOK)
+	fileLog.stop(nil, exitOK)
+}
+
+// traceCall prints an llt.exe invocation and its duration for --verbose
+func traceCall(call llt.Call) {
+	msg := fmt.Sprintf("llt.exe %s took %s", strings.Join(call.Args, " "), call.Duration.Round(time.Millisecond))
+	if call.Err != nil {
+		msg += fmt.Sprintf(" (%v)", call.Err)
+	}
+	msg += "\n"
+
+	writeToConsole(msg)
+	fmt.Fprint(os.Stderr, msg)
+}
+
+// traceSpawns prints how many llt.exe processes a command started for
+// --verbose, counting the startup IsRunning check and any busy retries.
+// Toggle needs at least three (check, get, set) until LLT offers a single
+// "next mode" command.
+func traceSpawns(command string, calls []llt.Call) {
+	msg := fmt.Sprintf("%s: %d llt.exe spawns\n", command, len(calls))
+	writeToConsole(msg)
+	fmt.Fprint(os.Stderr, msg)
+}
+
+// customMetadata converts the config's mode entries to manager metadata
+func customMetadata(cfg *config.Config) map[modes.PowerMode]modes.ModeMetadata {
+	custom := make(map[modes.PowerMode]modes.ModeMetadata, len(cfg.Modes))
+	for id, mc := range cfg.Modes {
+		position := mc.ToastPosition
+		if position != "" && !toast.IsValidPosition(position) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring invalid toastPosition '%s' for mode %s\n", position, id)
+			position = ""
+		}
+		message := mc.ToastMessage
+		if _, err := parseToastMessage(message); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring invalid toastMessage for mode %s: %v\n", id, err)
+			message = ""
+		}
+		custom[modes.PowerMode(id)] = modes.ModeMetadata{
+			Name:          mc.Name,
+			Description:   mc.Description,
+			IconPath:      mc.Icon,
+			Color:         mc.Color,
+			Symbol:        mc.Symbol,
+			ToastPosition: position,
+			ToastMessage:  message,
+		}
+	}
+	return custom
+}
+
+// programName returns the executable's base name for usage output, since
+// some launchers pass an absolute path or nothing at all as os.Args[0]
+func programName() string {
+	if len(os.Args) > 0 {
+		if name := filepath.Base(os.Args[0]); name != "" && name != "." && name != string(filepath.Separator) {
+			return name
+		}
 	}
+	return "llt-helper"
 }
 
 func printUsage() {
+	prog := programName()
 	usage := fmt.Sprintf(`Usage: %s [command] [flags]
 
 Commands:
   toggle              Cycle to next power mode in sequence
+  next                Like toggle, but skip modes this device doesn't offer and
+                      print the landing mode
   set --mode=MODE     Set specific power mode
   status              Show current power mode
+  auto                Pick the mode for the power source (--on-ac, --on-battery)
+                      and, with --apply, set it unless it's already active
+  list                List the known modes, marking the current one with *
+                      (--available-only: just those this device supports, in
+                      LLT's order or with --order=canonical in the cycle's)
+  modes               Show the toggle cycle with the current mode marked; --graph
+                      draws it on one line, --modes previews a custom cycle
+  lock --mode=MODE    Set a mode and refuse toggle/set until unlock (see --force)
+  unlock              Remove the lock set by lock
+  reset-state         Clear the helper's saved state (lock, last mode, debounce and
+                      cooldown times) after confirmation; device settings are kept
+  test-osd            Show a sample toast with the current toast settings
+                      (--title, --message, --osd-icon, --osd-color); LLT is not
+                      touched
+  monitors            List monitors by the number --toast-monitor takes
+                      (--identify flashes each number on its monitor)
+  whereis             Show where llt.exe was looked for and which one is used
+  config --show       Print the effective settings and where each came from
+  config export FILE  Save the config file's settings to FILE (import FILE restores
+                      them, e.g. on another machine)
+  enable-cli          Turn on the LLT CLI setting (restart LLT afterwards)
+  doctor              Check the LLT installation and CLI, with call timings
+  watch               Poll the power mode until stopped (see --enforce)
+  sensors             Show CPU/GPU temperatures and fan speeds
+  backlight           Show the keyboard backlight level (--toggle cycles it)
+  refresh-rate        Show the panel refresh rate (--toggle cycles it)
+  serve               Answer requests on \\.\pipe\llt-helper (status, list, toggle,
+                      set MODE, subscribe) from one long-running helper
+  schedule            Apply the mode of the schedule rule active now (see config)
+  hud                 Show a persistent on-screen indicator of the current mode
+  tray                Show the current mode as a notification area icon; click to
+                      toggle, right-click to pick a mode
+  profile list        List LLT automation profiles (Quick Actions)
+  profile set NAME    Run an LLT automation profile
+  feature get NAME    Print the value of any LLT feature (e.g. refresh-rate)
+  feature set NAME VALUE
+                      Set any LLT feature, as llt.exe f set does
+  preset NAME         Apply a saved preset
+  preset save NAME    Save current power mode and features as a preset
+
+Aliases:
+  perf, q, bal        Shorthand for set --mode=performance|quiet|balance
+                      (more can be defined under "aliases" in the config file)
+
+Without a command, runs LLT_HELPER_DEFAULT_COMMAND (e.g. "toggle") if set, else
+"defaultCommand" from the config file, else prints this help and exits 2.
 
 Global Flags:
-  --version           Show version information
-  --help, -h          Show this help message
+  --version           Show version, build and LLT information (--json supported)
+  --help, -h          Show this help message (COMMAND --help for one command)
+  --no-console        Don't attach to the parent console; write to stderr/stdout only
+                      (also set by LLT_HELPER_NO_CONSOLE=1)
+  --config path       Read the config file from path instead of
+                      %%APPDATA%%\llt-helper\config.json
+  --safe-mode         Create no windows at all: implies --no-toast and --no-console,
+                      and refuses hud, tray, test-osd and monitors --identify. On by
+                      default in Remote Desktop sessions (--safe-mode=false turns it off)
 
 Command Flags:
-  --mode string       Target mode (quiet|balance|performance)
+  --mode string       Target mode (quiet|balance|performance); - reads a single
+                      line from stdin, e.g. echo performance | llt-helper set --mode=-
+  --mode-index int    Target mode by LLT's numeric index (1 quiet, 2 balance,
+                      3 performance, 255 godmode), checked against available modes
+  --index int         Target mode by its 1-based position in the cycle (the config
+                      file's sequence, or the modes LLT lists), e.g. --index=1
   --modes string      Comma-separated modes for toggle (e.g., quiet,performance)
-  --no-toast          Suppress toast notification
+  --modes-ac, --modes-battery list
+                      Like --modes, but only on that power source (toggle, next);
+                      --modes wins, and without one for the current source the
+                      usual cycle is used
+  --reverse           With toggle, go to the previous mode instead (the prev alias);
+                      from a mode outside the cycle it lands on the last one
+  --on-ac, --on-battery mode
+                      The modes auto picks between
+  --apply             With auto, set the picked mode rather than just print it
+  --revert-to mode, --revert-after duration
+                      With set, switch back to this mode after the duration, e.g.
+                      --revert-to=balance --revert-after=10m (skipped if the mode
+                      was changed meanwhile)
+  --exit-on-noop      With set or auto, exit with code 7 instead of 0 when the
+                      mode is already active and nothing was changed
+  --force             Change the mode even while it's locked; for set, also re-apply
+                      (and toast) a mode that is already active
+  --broadcast         After each mode change, pulse the event Local\LLTHelperModeChanged
+                      and broadcast the "LLTHelperModeChanged" window message
+  --confirm           Ask "Apply GodMode? [y/N]" before switching to a custom mode;
+                      without a console (e.g. Stream Deck) --yes is required instead
+  --yes               Skip the --confirm prompt
+  --only-on source    Only toggle/set while on battery or ac; otherwise do nothing
+                      (with a toast unless --no-toast)
+  --unknown-fallback  Where toggle goes from a mode outside the cycle (e.g. godmode):
+                      first (default), balance, or last (the helper's last set mode)
+  --no-toast          Suppress toast notification ("toast": false in the config file
+                      makes this the default; --no-toast=false turns toasts back on)
+  --toast-multiline   Word-wrap long toast messages and grow the OSD to fit
+  --icon-theme name   Use icons from assets/icons/<name>/ (falls back to assets/icons/)
+  --toast-animation   Toast entrance/exit animation: none (default), fade or slide
+  --toast-delay dur   Wait before showing the toast, after the mode is set (max 10s)
+  --toast-duration d  How long the toast stays up, 500ms-30s (default 3s, or
+                      "osdDurationMs" from the config file)
+  --toast-position    Where the toast appears: top-left, top-center, top-right, center,
+                      bottom-left, bottom-center (default) or bottom-right
+                      (modes can override it with "toastPosition" in the config file)
+  --toast-cooldown d  Skip a toast shown less than this long after the previous one
+                      (off by default)
+  --toast-text-shadow Draw a drop shadow under the toast text for legibility
+  --toast-wait=false  Exit as soon as the mode is set, leaving the toast to a
+                      detached process (for scripted chains and snappy keys);
+                      preset then shows one stacked toast per change. Also
+                      --osd-async, or "toastWait": false in the config file
+  --toast-stack       Stack the toast with other helper toasts still showing
+                      (up to 4) instead of drawing over them
+  --toast-monitor n   Show the toast on monitor n as listed by the monitors command
+                      (default 0: the monitor with the active window, or the
+                      primary if there is none; also used if n is unplugged)
+  --toast-show-battery
+                      Add the battery charge level as a second line in mode change
+                      toasts (left out on machines without a battery)
+  --toast-scale f     Make the toast f times its normal size, fonts included
+                      (0.5-3.0, default 1)
+  --toast-theme string
+                      Toast colors: dark (default), light, or auto to follow the
+                      Windows light/dark app setting, checked on every toast
+  --toast-max-width n Let the toast widen up to n pixels to fit long titles and
+                      messages (default 600, 400 keeps it fixed); text that still
+                      doesn't fit ends in an ellipsis
+  --toast-progress    Draw a thin bar along the toast's bottom edge counting down
+                      until it closes; preset also shows each change as it's
+                      applied with a bar filling up
+  --toast-no-topmost  Don't force the toast above other windows, so it can't cover
+                      UAC prompts or other dialogs (topmost by default)
+  --interval duration Polling interval for watch, hud, tray and serve (default 2s)
+  --enforce string    Mode for watch to re-apply whenever it drifts
+  --cooldown duration Minimum time between --enforce corrections (default 10s)
+  --toast-on-enforce  Show a toast for each --enforce correction
+  --toast-on-change   Make watch show a toast for each mode change it detects
+  --toast-source src  Which detected changes get a toast: all (default), external
+                      (made outside the helper, e.g. in the LLT GUI) or self
+  --error-summary-interval dur
+                      In watch/serve, show one toast per interval counting LLT
+                      errors (e.g. "3 LLT errors in the last 5m0s"); off by default
+  --schedule          Make watch apply schedule rules as each time window starts
+  --features list     Make watch also read these LLT features (power-mode included)
+                      and print a JSON line per change, e.g.
+                      --features=power-mode,battery
+  --json              Output machine-readable JSON (status, list, doctor, sensors,
+                      config, whereis, monitors); watch prints one status line
+                      per mode change
+  --short             Print only a one-character symbol for the mode (status)
+  --toast             Also show the current mode as a "Current Power Mode" toast,
+                      changing nothing (status)
+  --icon              Print only the absolute path of the mode's icon, or an empty
+                      line if there is none (status; JSON has it as "icon")
+  --format template   Shape the status output with a Go template over the fields
+                      Mode, Name, Symbol, Color, IconPath, Icon, Index and Timestamp,
+                      e.g. --format="{{.Symbol}} {{.Name}}"
+  --write path        Also write the current mode to a file (status); watch rewrites
+                      it on every change, for Rainmeter/OBS text sources
+  --write-format tmpl Go template for the --write file, same fields as --format
+                      (default "{{.Name}}")
+  --with-timestamp    Add when the mode was read, in ISO 8601 UTC: a "timestamp"
+                      field in status --json and --write/--format templates, and a
+                      prefix on status and watch lines (watch then also prints
+                      each change it detects)
+  --local-time        Use local time (with its UTC offset) for --with-timestamp
+  --strict            Make status fail (exit code 3) on a mode it doesn't recognize
+  --llt-arg arg       ADVANCED, UNSAFE: append arg to every llt.exe get/set call, for
+                      trying LLT flags the helper doesn't wrap (repeatable; not
+                      validated, check the result with --verbose)
+  --etw               Write events for the command's start and end, each llt.exe
+                      call and errors to the LLTHelper ETW provider (see README)
+  --log-file path     Append timestamped entries (LLT path, llt.exe calls and exit
+                      codes, mode changes, toasts, the result) to path; also set
+                      by LLT_HELPER_LOG. With --verbose, full llt.exe command lines
+  --verbose           Print each llt.exe invocation and how long it took, and the
+                      number of llt.exe processes the command started; status
+                      also shows the battery level and lists LLT automation
+                      that may change the mode
+  --debounce duration Skip toggle/set if the mode changed less than this long ago
+                      (off by default)
+  --elevate           When an operation needs administrator rights (e.g. enable-cli
+                      writing LLT's settings), relaunch the same command through
+                      the UAC prompt instead of failing
+  --single-instance p When another helper is already changing settings: wait
+                      (default, up to 10s), fail (exit code 6) or allow
+  --wait-for-llt dur  Keep retrying this long for LLT to be installed and responding
+                      (e.g. when run at login); off by default
+  --llt-path path     Use the llt.exe at path instead of looking in the usual
+                      install locations (also set by LLT_PATH)
+  --timeout duration  How long to wait for each llt.exe call (default 5s)
+  --max-rate n        Start at most n llt.exe processes per second; calls over the
+                      rate wait their turn instead of failing (off by default,
+                      useful for watch and serve)
+  --retries n         Retry an llt.exe call that fails unexpectedly up to n times,
+                      within --timeout (default 2, 0 = off)
+  --retry-delay dur   Wait before the first retry, doubling after each (default 200ms)
+  --read-source       Where to read the current mode: cli (default), wmi, or
+                      auto (CLI first, then the Lenovo WMI interface)
+  --mode-map list     Translate mode names LLT prints to mode ids, for builds the
+                      helper doesn't recognize, e.g. --mode-map="Turbo=performance"
+                      (merged over "modeMap" in the config file)
 
 Examples:
   %s toggle
   %s set --mode=balance
   %s toggle --no-toast
   %s toggle --modes=quiet,performance
-`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+  %s preset save gaming
+`, prog, prog, prog, prog, prog, prog)
 
 	writeToConsole(usage)
 	// Also write to stderr for non-console contexts
 	fmt.Fprint(os.Stderr, usage)
 }
 
-func handleToggle(client *llt.Client, manager *modes.Manager, notifier *toast.Notifier, modesFlag string) error {
-	current, err := client.GetCurrentMode()
+func handleToggle(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, modesFlag string, reverse bool, confirmOpts confirmOptions) error {
+	allowedModes, err := parseModesFlag(manager, modesFlag)
 	if err != nil {
 		return err
 	}
 
-	var allowedModes []modes.PowerMode
-	if modesFlag != "" {
-		// Parse comma-separated modes
-		parts := strings.Split(modesFlag, ",")
-		for _, part := range parts {
-			trimmed := strings.TrimSpace(part)
-			if trimmed == "" {
-				continue
-			}
-			if !manager.IsValidMode(trimmed) {
-				return fmt.Errorf("invalid mode '%s' in --modes flag", trimmed)
-			}
-			allowedModes = append(allowedModes, modes.PowerMode(trimmed))
-		}
-		if len(allowedModes) == 0 {
-			return fmt.Errorf("no valid modes specified in --modes flag")
-		}
-	}
-
-	next := manager.GetNextModeFromList(modes.PowerMode(current), allowedModes)
-	err = client.SetMode(string(next))
-	if err != nil {
-		return err
-	}
+	_, err = cycleMode(client, manager, notifier, allowedModes, reverse, confirmOpts)
+	return err
+}
 
-	if notifier != nil {
-		meta := manager.GetModeMetadata(next)
-		if err := notifier.ShowModeChange(meta.Name, meta.IconPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
-			// Don't exit, as mode was set successfully
-		}
+// cycleMode moves to the mode after the current one in allowedModes (the
+// default sequence when empty), or before it with reverse, and shows its
+// toast, returning that mode
+func cycleMode(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, allowedModes []modes.PowerMode, reverse bool, confirmOpts confirmOptions) (modes.PowerMode, error) {
+	opts := appOptions(manager, confirmOpts)
+	opts.Modes = allowedModes
+	opts.Reverse = reverse
+	opts.Changed = func(mode string) {
+		modeChanged(mode)
+		// The read-modify-write is done; let a queued press go ahead
+		releaseInstance()
 	}
-
-	return nil
+	return app.Toggle(client, manager, notifier, opts)
 }
 
-func handleSet(client *llt.Client, manager *modes.Manager, mode string, notifier *toast.Notifier) error {
-	if !manager.IsValidMode(mode) {
-		return fmt.Errorf("unknown power mode: %s", mode)
-	}
+// errAlreadyActive is returned by handleSet when it leaves a mode that is
+// already active alone; callers treat it as success
+var errAlreadyActive = app.ErrAlreadyActive
 
-	err := client.SetMode(mode)
-	if err != nil {
-		return err
+// handleSet switches to mode. Unless force is set, a mode that is already
+// active is left alone, without a toast, returning errAlreadyActive, so
+// re-asserting it (e.g. from a Stream Deck multi-action) doesn't spawn
+// llt.exe to set it again. A mode of "-" is read from stdin.
+func handleSet(client *llt.Client, manager *modes.Manager, mode string, notifier toast.Notifier, confirmOpts confirmOptions, force bool) error {
+	if mode == stdinMode {
+		var err error
+		if mode, err = readModeFromStdin(); err != nil {
+			return err
+		}
 	}
 
-	if notifier != nil {
+	opts := appOptions(manager, confirmOpts)
+	opts.Force = force
+	err := app.Set(client, manager, notifier, mode, opts)
+	if errors.Is(err, errAlreadyActive) {
 		meta := manager.GetModeMetadata(modes.PowerMode(mode))
-		if err := notifier.ShowModeChange(meta.Name, meta.IconPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
-		}
+		printOut(fmt.Sprintf("Already in %s\n", meta.Name))
 	}
+	return err
+}
 
-	return nil
+// appOptions wires the --confirm guard, the toastMessage templates and the
+// state file and broadcast updates into the app package's toggle and set
+func appOptions(manager *modes.Manager, confirmOpts confirmOptions) app.Options {
+	return app.Options{
+		Confirm: func(mode string) error {
+			fileLog.info("target mode %s", mode)
+			return confirmOpts.check(manager, mode)
+		},
+		Changed: modeChanged,
+		Message: modeChangeMessage,
+		Warn: func(err error) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		},
+	}
 }
 
-func handleStatus(client *llt.Client, manager *modes.Manager) error {
-	current, err := client.GetCurrentMode()
-	if err != nil {
+// setModeVerified sets the power mode like app.SetVerified, then records it
+// as the last mode and announces the change
+func setModeVerified(client *llt.Client, manager *modes.Manager, mode string) error {
+	if err := app.SetVerified(client, manager, mode); err != nil {
 		return err
 	}
-
-	meta := manager.GetModeMetadata(modes.PowerMode(current))
-	statusMsg := fmt.Sprintf("Current Mode: %s (%s)\n", meta.Name, current)
-	writeToConsole(statusMsg)
-	fmt.Print(statusMsg)
+	modeChanged(mode)
 	return nil
 }
+
+// modeChanged records a mode the helper set and announces it to listeners
+func modeChanged(mode string) {
+	fileLog.info("mode set to %s", mode)
+	recordLastMode(mode)
+	announceModeChange(mode)
+}
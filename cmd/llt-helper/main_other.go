@@ -0,0 +1,15 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// The helper drives llt.exe and draws Win32 overlays, so elsewhere it only
+// builds (for tooling and CI) and explains itself
+func main() {
+	fmt.Fprintf(os.Stderr, "Error: llt-helper only runs on Windows\n")
+	os.Exit(1)
+}
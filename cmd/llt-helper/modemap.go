@@ -0,0 +1,46 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+)
+
+// resolveModeMap merges the config file's modeMap with --mode-map
+// ("Name=id,Other=id"), which wins for the same name. Names are lowercased
+// for the client's case-insensitive lookup.
+func resolveModeMap(cfg *config.Config, flagValue string) (map[string]string, error) {
+	modeMap := make(map[string]string, len(cfg.ModeMap))
+	for name, id := range cfg.ModeMap {
+		modeMap[strings.ToLower(strings.TrimSpace(name))] = strings.ToLower(strings.TrimSpace(id))
+	}
+
+	for _, entry := range strings.Split(flagValue, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, id, ok := strings.Cut(entry, "=")
+		name, id = strings.TrimSpace(name), strings.TrimSpace(id)
+		if !ok || name == "" || id == "" {
+			return nil, fmt.Errorf("invalid --mode-map entry '%s' (use NAME=MODE)", entry)
+		}
+		modeMap[strings.ToLower(name)] = strings.ToLower(id)
+	}
+
+	return modeMap, nil
+}
+
+// warnUnknownMappedModes reports mode map targets the helper doesn't know,
+// which would still show up as unrecognized modes
+func warnUnknownMappedModes(manager *modes.Manager, modeMap map[string]string) {
+	for _, name := range sortedKeys(modeMap) {
+		if id := modeMap[name]; !manager.IsValidMode(id) {
+			fmt.Fprintf(os.Stderr, "Warning: mode map entry '%s' points to unknown mode '%s'\n", name, id)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package main
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+)
+
+func TestResolveModeMap(t *testing.T) {
+	cfg := &config.Config{ModeMap: map[string]string{" Turbo ": "Performance", "Whisper": "quiet"}}
+
+	got, err := resolveModeMap(cfg, "whisper=balance, Eco = Quiet,")
+	if err != nil {
+		t.Fatalf("resolveModeMap: %v", err)
+	}
+	want := map[string]string{"turbo": "performance", "whisper": "balance", "eco": "quiet"}
+	if !maps.Equal(got, want) {
+		t.Errorf("resolveModeMap = %v, want %v", got, want)
+	}
+}
+
+func TestResolveModeMapInvalid(t *testing.T) {
+	for _, flag := range []string{"turbo", "=quiet", "turbo=", " = "} {
+		if _, err := resolveModeMap(&config.Config{}, flag); err == nil {
+			t.Errorf("resolveModeMap(%q) succeeded, want an error", flag)
+		}
+	}
+}
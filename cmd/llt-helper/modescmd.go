@@ -0,0 +1,61 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+)
+
+// handleModes prints the cycle toggle follows (the --modes list, or the
+// default sequence, see Manager.CycleFor) with the current mode marked. It changes nothing, so a
+// --modes list can be checked before it's bound to a button. With graph the
+// cycle is drawn on one line: quiet → [balance] → performance → (wrap).
+func handleModes(client *llt.Client, manager *modes.Manager, modesFlag string, graph bool) error {
+	allowedModes, err := parseModesFlag(manager, modesFlag)
+	if err != nil {
+		return err
+	}
+
+	// The cycle is still worth showing when the mode can't be read
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	cycle := manager.CycleFor(modes.PowerMode(current), allowedModes)
+
+	var b strings.Builder
+	if graph {
+		for _, mode := range cycle {
+			if string(mode) == current {
+				fmt.Fprintf(&b, "[%s] → ", mode)
+			} else {
+				fmt.Fprintf(&b, "%s → ", mode)
+			}
+		}
+		b.WriteString("(wrap)\n")
+	} else {
+		for _, mode := range cycle {
+			marker := " "
+			if string(mode) == current {
+				marker = "*"
+			}
+			fmt.Fprintf(&b, "%s %-12s %s\n", marker, mode, manager.GetModeMetadata(mode).Name)
+		}
+	}
+
+	if current != "" {
+		if !slices.Contains(cycle, modes.PowerMode(current)) {
+			next := manager.GetNextModeFromList(modes.PowerMode(current), allowedModes)
+			fmt.Fprintf(&b, "Current mode %s is not in the cycle; toggle goes to %s\n", current, next)
+		}
+	}
+
+	printOut(b.String())
+	return nil
+}
@@ -0,0 +1,81 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// monitorRect is a rectangle in virtual-screen coordinates
+type monitorRect struct {
+	X      int32 `json:"x"`
+	Y      int32 `json:"y"`
+	Width  int32 `json:"width"`
+	Height int32 `json:"height"`
+}
+
+// monitorInfo is one display in the monitors command's output
+type monitorInfo struct {
+	Index    int         `json:"index"`
+	Primary  bool        `json:"primary"`
+	Bounds   monitorRect `json:"bounds"`
+	WorkArea monitorRect `json:"workArea"`
+}
+
+func toMonitorRect(r toast.RECT) monitorRect {
+	return monitorRect{X: r.Left, Y: r.Top, Width: r.Right - r.Left, Height: r.Bottom - r.Top}
+}
+
+// handleMonitors lists the displays by the index --toast-monitor takes. With
+// identify, a toast showing its number is flashed on each display in turn.
+func handleMonitors(osd *toast.OSDNotifier, jsonOut, identify bool) error {
+	monitors, err := toast.Monitors()
+	if err != nil {
+		return err
+	}
+
+	infos := make([]monitorInfo, 0, len(monitors))
+	for _, m := range monitors {
+		infos = append(infos, monitorInfo{
+			Index:    m.Index,
+			Primary:  m.Primary,
+			Bounds:   toMonitorRect(m.Bounds),
+			WorkArea: toMonitorRect(m.WorkArea),
+		})
+	}
+
+	if jsonOut {
+		data, err := json.Marshal(infos)
+		if err != nil {
+			return fmt.Errorf("failed to encode monitors: %w", err)
+		}
+		printOut(string(data) + "\n")
+	} else {
+		var b strings.Builder
+		for _, m := range infos {
+			primary := ""
+			if m.Primary {
+				primary = " (primary)"
+			}
+			fmt.Fprintf(&b, "%d. %dx%d at %d,%d, work area %dx%d%s\n",
+				m.Index, m.Bounds.Width, m.Bounds.Height, m.Bounds.X, m.Bounds.Y,
+				m.WorkArea.Width, m.WorkArea.Height, primary)
+		}
+		printOut(b.String())
+	}
+
+	if identify {
+		for _, m := range infos {
+			osd.Monitor = m.Index
+			if err := osd.Show(fmt.Sprintf("Monitor %d", m.Index), fmt.Sprintf("--toast-monitor=%d", m.Index)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
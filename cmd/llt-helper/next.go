@@ -0,0 +1,53 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handleNext is toggle for a single button: it moves forward through the
+// --modes list (or the default cycle) restricted to the modes this device
+// offers, and prints where it landed. If LLT can't list the available
+// modes, the whole cycle is used.
+func handleNext(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, modesFlag string, confirmOpts confirmOptions) error {
+	allowedModes, err := parseModesFlag(manager, modesFlag)
+	if err != nil {
+		return err
+	}
+	cycle := manager.Cycle(allowedModes)
+
+	if available, err := client.ListAvailableModes(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: can't list available modes, not skipping any: %v\n", err)
+	} else {
+		offered := availableModes(cycle, available, orderCanonical)
+		if len(offered) == 0 {
+			return fmt.Errorf("none of %s is available on this device", joinModes(cycle))
+		}
+		cycle = offered
+	}
+
+	next, err := cycleMode(client, manager, notifier, cycle, false, confirmOpts)
+	if err != nil {
+		return err
+	}
+
+	pos, total := manager.CyclePosition(next, cycle)
+	printOut(fmt.Sprintf("Now in %s (%d/%d)\n", manager.GetModeMetadata(next).Name, pos, total))
+	return nil
+}
+
+// joinModes formats modes as a comma-separated list
+func joinModes(list []modes.PowerMode) string {
+	names := make([]string, len(list))
+	for i, mode := range list {
+		names[i] = string(mode)
+	}
+	return strings.Join(names, ", ")
+}
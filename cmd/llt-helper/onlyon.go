@@ -0,0 +1,97 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+)
+
+// --only-on values
+const (
+	onlyOnBattery = "battery"
+	onlyOnAC      = "ac"
+)
+
+func isValidOnlyOn(onlyOn string) bool {
+	switch onlyOn {
+	case "", onlyOnBattery, onlyOnAC:
+		return true
+	}
+	return false
+}
+
+// powerSource returns onlyOnAC or onlyOnBattery for the current power source
+func powerSource() (string, error) {
+	onAC, err := llt.OnACPower()
+	if err != nil {
+		return "", err
+	}
+	if onAC {
+		return onlyOnAC, nil
+	}
+	return onlyOnBattery, nil
+}
+
+// onlyOnMismatch returns the current power source when it isn't the one
+// --only-on asks for, or "" when the command should go ahead. If the power
+// source can't be determined the command goes ahead.
+func onlyOnMismatch(onlyOn string) (string, error) {
+	if onlyOn == "" {
+		return "", nil
+	}
+
+	source, err := powerSource()
+	if err != nil {
+		return "", err
+	}
+	if source == onlyOn {
+		return "", nil
+	}
+	return source, nil
+}
+
+// powerSourceName is the display form of an --only-on value
+func powerSourceName(source string) string {
+	if source == onlyOnAC {
+		return "AC"
+	}
+	return source
+}
+
+// powerSourceCycle returns the --modes list for toggle and next on the
+// current power source: --modes-ac or --modes-battery, else the config's
+// sequenceAC or sequenceBattery. It returns "" (the usual cycle) when none
+// is set for the source, or when the source can't be determined.
+func powerSourceCycle(manager *modes.Manager, cfg *config.Config, modesAC, modesBattery string) (string, error) {
+	if modesAC == "" && modesBattery == "" && len(cfg.SequenceAC) == 0 && len(cfg.SequenceBattery) == 0 {
+		return "", nil
+	}
+	for _, flag := range []struct{ name, list string }{{"modes-ac", modesAC}, {"modes-battery", modesBattery}} {
+		for _, part := range strings.Split(flag.list, ",") {
+			if mode := strings.TrimSpace(part); mode != "" && !manager.IsValidMode(mode) {
+				return "", fmt.Errorf("invalid mode '%s' in --%s", mode, flag.name)
+			}
+		}
+	}
+
+	source, err := powerSource()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using the usual cycle\n", err)
+		return "", nil
+	}
+
+	list, key, configList := modesAC, "sequenceAC", cfg.SequenceAC
+	if source == onlyOnBattery {
+		list, key, configList = modesBattery, "sequenceBattery", cfg.SequenceBattery
+	}
+	if list != "" {
+		return list, nil
+	}
+	return joinModes(configSequence(manager, key, configList)), nil
+}
@@ -0,0 +1,176 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handlePreset dispatches `preset save NAME` and `preset NAME`
+// With stack, applying a preset shows a stack of confirmations, one per
+// change, instead of a single mode-change toast. With progress, an OSD
+// with a progress bar follows the changes as they are applied.
+func handlePreset(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, cfg *config.Config, configPath string, args []string, stack, progress bool) error {
+	if len(args) == 2 && args[0] == "save" {
+		return handlePresetSave(client, configPath, args[1])
+	}
+	if len(args) == 1 && args[0] != "save" {
+		return handlePresetApply(client, manager, notifier, cfg, args[0], stack, progress)
+	}
+	return fmt.Errorf("usage: preset NAME | preset save NAME")
+}
+
+// handlePresetSave captures the current power mode and configured features
+// into a named preset. Features that can't be read are left out. The config
+// file is read again rather than trusting cfg, which main falls back to
+// defaults for when the file can't be parsed; saving that would replace
+// the user's whole config with just this preset.
+func handlePresetSave(client *llt.Client, configPath, name string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("not saving preset '%s': %w (fix or move the config file first)", name, err)
+	}
+
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		return err
+	}
+
+	preset := config.Preset{
+		PowerMode: current,
+		Features:  make(map[string]string),
+	}
+
+	for _, feature := range cfg.GetPresetFeatures() {
+		value, err := client.GetFeature(feature)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: omitting %s from preset: %v\n", feature, err)
+			continue
+		}
+		preset.Features[feature] = value
+	}
+
+	if cfg.Presets == nil {
+		cfg.Presets = make(map[string]config.Preset)
+	}
+	cfg.Presets[name] = preset
+
+	if err := cfg.Save(configPath); err != nil {
+		return err
+	}
+
+	printOut(fmt.Sprintf("Saved preset '%s' (%s, %d features)\n", name, current, len(preset.Features)))
+	return nil
+}
+
+// handlePresetApply re-applies a saved preset: power mode first, then features
+func handlePresetApply(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, cfg *config.Config, name string, stack, progress bool) error {
+	preset, ok := cfg.Presets[name]
+	if !ok {
+		return fmt.Errorf("unknown preset: %s", name)
+	}
+
+	// Refuse up front rather than leave the preset half applied
+	if conflicts := preset.Conflicts(); len(conflicts) > 0 {
+		return fmt.Errorf("preset '%s' has conflicting features, nothing applied:\n  %s", name, strings.Join(conflicts, "\n  "))
+	}
+
+	steps := len(preset.Features)
+	if preset.PowerMode != "" {
+		steps++
+	}
+	tracker := newPresetProgress(fmt.Sprintf("Preset '%s'", name), steps, progress)
+
+	if preset.PowerMode != "" {
+		tracker.step(fmt.Sprintf("power-mode: %s", preset.PowerMode))
+		if err := setModeVerified(client, manager, preset.PowerMode); err != nil {
+			tracker.close()
+			return err
+		}
+	}
+
+	var failed, applied []string
+	for _, feature := range sortedKeys(preset.Features) {
+		value := preset.Features[feature]
+		tracker.step(fmt.Sprintf("%s: %s", feature, value))
+		if err := client.SetFeature(feature, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			failed = append(failed, feature)
+			continue
+		}
+		applied = append(applied, fmt.Sprintf("%s: %s", feature, value))
+	}
+	tracker.close()
+
+	if stack {
+		var lines []string
+		if preset.PowerMode != "" {
+			meta := manager.GetModeMetadata(modes.PowerMode(preset.PowerMode))
+			lines = append(lines, modeChangeMessage(meta, meta.Name))
+		}
+		showStacked(notifier, fmt.Sprintf("Preset '%s'", name), append(lines, applied...))
+	} else if preset.PowerMode != "" {
+		meta := manager.GetModeMetadata(modes.PowerMode(preset.PowerMode))
+		if err := notifier.ShowModeChange(modeChangeMessage(meta, meta.Name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("preset '%s' partially applied, failed features: %s", name, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// presetProgress shows the changes of a preset being applied on a HUD with
+// a progress bar. A zero presetProgress (no --toast-progress, or the HUD
+// couldn't be created) does nothing.
+type presetProgress struct {
+	hud   *toast.HUD
+	title string
+	steps int
+	done  int
+}
+
+// newPresetProgress opens the progress HUD when enabled
+func newPresetProgress(title string, steps int, enabled bool) *presetProgress {
+	if !enabled || steps == 0 {
+		return &presetProgress{}
+	}
+	hud, err := toast.NewHUD(title, "Applying...")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: progress OSD not shown: %v\n", err)
+		return &presetProgress{}
+	}
+	hud.SetProgress(0)
+	return &presetProgress{hud: hud, title: title, steps: steps}
+}
+
+// step shows the change about to be applied, with the bar filled to the
+// share of the changes already made
+func (p *presetProgress) step(change string) {
+	if p.hud == nil {
+		return
+	}
+	p.hud.Update(p.title, change)
+	p.hud.SetProgress(float64(p.done) / float64(p.steps))
+	p.done++
+}
+
+// close fills the bar and removes the HUD, ahead of the confirmation toast
+func (p *presetProgress) close() {
+	if p.hud == nil {
+		return
+	}
+	p.hud.SetProgress(1)
+	p.hud.Close()
+	p.hud = nil
+}
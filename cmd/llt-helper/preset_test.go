@@ -0,0 +1,27 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPresetSaveKeepsMalformedConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	malformed := []byte(`{"sequence": ["quiet", "performance"], "presets": {`)
+	if err := os.WriteFile(path, malformed, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The config is checked before LLT is asked anything, so no client is needed
+	if err := handlePresetSave(nil, path, "work"); err == nil {
+		t.Fatal("preset save succeeded with a malformed config, want an error")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != string(malformed) {
+		t.Errorf("config = %q, %v; want it untouched", data, err)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// handleProfile dispatches `profile list` and `profile set NAME`
+func handleProfile(client *llt.Client, args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		profiles, err := client.ListProfiles()
+		if err != nil {
+			return err
+		}
+		if len(profiles) == 0 {
+			printOut("No profiles defined in LLT\n")
+			return nil
+		}
+		printOut(strings.Join(profiles, "\n") + "\n")
+		return nil
+
+	case len(args) == 2 && args[0] == "set":
+		if err := client.ActivateProfile(args[1]); err != nil {
+			return err
+		}
+		printOut(fmt.Sprintf("Activated profile '%s'\n", args[1]))
+		return nil
+	}
+
+	return fmt.Errorf("usage: profile list | profile set NAME")
+}
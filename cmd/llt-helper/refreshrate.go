@@ -0,0 +1,46 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handleRefreshRate prints the panel refresh rate or, with toggle, advances
+// it to the next rate the panel supports, wrapping around
+func handleRefreshRate(client *llt.Client, notifier toast.Notifier, toggle bool) error {
+	current, err := client.GetRefreshRate()
+	if err != nil {
+		return err
+	}
+
+	if !toggle {
+		printOut(fmt.Sprintf("Refresh rate: %s\n", current))
+		return nil
+	}
+
+	rates, err := client.RefreshRates()
+	if err != nil {
+		return err
+	}
+	if len(rates) == 1 {
+		printOut(fmt.Sprintf("Refresh rate: %s (the only rate this panel supports)\n", current))
+		return nil
+	}
+
+	next := nextValue(rates, current)
+	if err := client.SetRefreshRate(next); err != nil {
+		return err
+	}
+	printOut(fmt.Sprintf("Refresh rate: %s\n", next))
+
+	if err := notifier.Show("Refresh Rate", next); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/state"
+)
+
+// handleResetState deletes the helper's state file (lock, last mode, debounce
+// and toast cooldown timestamps) after confirmation, listing what it held.
+// Device settings and the config file are left alone.
+func handleResetState(yes bool) error {
+	path := state.DefaultPath()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		printOut("No helper state to clear\n")
+		return nil
+	}
+
+	// An unreadable file is still removed; that's often why state is reset
+	st, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	held := describeState(st)
+
+	if !yes {
+		ok, err := askYesNo(fmt.Sprintf("Clear helper state in %s?", path))
+		if err != nil {
+			return fmt.Errorf("state not cleared: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("cancelled, state not cleared")
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove state %s: %w", path, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cleared helper state in %s\n", path)
+	for _, line := range held {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+	printOut(b.String())
+	return nil
+}
+
+// describeState lists the entries set in st, one line each
+func describeState(st *state.State) []string {
+	var lines []string
+	if st.LockedMode != "" {
+		lines = append(lines, "lock: "+st.LockedMode)
+	}
+	if st.PendingRevert != nil {
+		lines = append(lines, fmt.Sprintf("pending revert: to %s at %s", st.PendingRevert.Mode, st.PendingRevert.At.Local().Format(time.DateTime)))
+	}
+	if st.LastMode != "" {
+		lines = append(lines, fmt.Sprintf("last mode: %s (set %s)", st.LastMode, st.LastModeAt.Format(time.DateTime)))
+	}
+	if !st.LastChange.IsZero() {
+		lines = append(lines, "last change (--debounce): "+st.LastChange.Format(time.DateTime))
+	}
+	if !st.LastToast.IsZero() {
+		lines = append(lines, "last toast (--toast-cooldown): "+st.LastToast.Format(time.DateTime))
+	}
+	return lines
+}
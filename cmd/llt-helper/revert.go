@@ -0,0 +1,138 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/state"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// revertCommand is the hidden command a detached process runs to wait for
+// a pending revert and apply it
+const revertCommand = "revert-pending"
+
+// scheduleRevert records a revert to mode after the given delay in the
+// state file and starts a detached process that applies it then, so set
+// itself returns (and releases the instance mutex) right away. If the mode
+// set now has been changed by the time the revert is due, the revert is
+// dropped. If the waiting process is killed, the next helper command or
+// watch poll applies the overdue revert instead.
+func scheduleRevert(client *llt.Client, mode string, after time.Duration) error {
+	from, err := client.GetCurrentMode()
+	if err != nil {
+		return fmt.Errorf("revert not scheduled: %w", err)
+	}
+
+	path := state.DefaultPath()
+	st, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	st.PendingRevert = &state.PendingRevert{Mode: mode, From: from, At: time.Now().Add(after)}
+	if err := st.Save(path); err != nil {
+		return fmt.Errorf("revert not scheduled: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to start revert process (the next helper command applies it when due): %w", err)
+	}
+	cmd := exec.Command(exe, append([]string{revertCommand, "--no-console"}, configArgs()...)...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP,
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start revert process (the next helper command applies it when due): %w", err)
+	}
+	cmd.Process.Release()
+
+	printOut(fmt.Sprintf("Reverting to %s in %s\n", mode, after))
+	return nil
+}
+
+// pendingRevert returns the revert recorded in the state file, if any
+func pendingRevert() *state.PendingRevert {
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return st.PendingRevert
+}
+
+// handleRevertPending waits for the pending revert to fall due and applies
+// it. A revert rescheduled meanwhile is waited for in turn; one cancelled
+// (applied by another helper, or cleared by reset-state) ends the wait.
+func handleRevertPending(client *llt.Client, manager *modes.Manager, notifier toast.Notifier) error {
+	for {
+		pending := pendingRevert()
+		if pending == nil {
+			return nil
+		}
+		if wait := time.Until(pending.At); wait > 0 {
+			time.Sleep(wait)
+			continue
+		}
+
+		// Two waiting processes (a revert rescheduled by a second set) wake
+		// together; only the one holding the mutex finds the revert still
+		// pending
+		if err := acquireInstance(singleInstanceWait); err != nil {
+			return err
+		}
+		return completeOverdueRevert(client, manager, notifier)
+	}
+}
+
+// completeOverdueRevert applies the pending revert if it's due. It's
+// removed from the state first, so it's applied at most once. The revert is
+// skipped when the mode was changed since it was scheduled or is locked.
+func completeOverdueRevert(client *llt.Client, manager *modes.Manager, notifier toast.Notifier) error {
+	path := state.DefaultPath()
+	st, err := state.Load(path)
+	if err != nil {
+		return err
+	}
+	pending := st.PendingRevert
+	if pending == nil || time.Now().Before(pending.At) {
+		return nil
+	}
+
+	st.PendingRevert = nil
+	if err := st.Save(path); err != nil {
+		return err
+	}
+
+	if st.LockedMode != "" {
+		printOut(fmt.Sprintf("Not reverting to %s: the mode is locked to %s\n", pending.Mode, st.LockedMode))
+		return nil
+	}
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		return fmt.Errorf("revert to %s dropped: %w", pending.Mode, err)
+	}
+	if current != pending.From {
+		printOut(fmt.Sprintf("Not reverting to %s: the mode was changed to %s since\n", pending.Mode, current))
+		return nil
+	}
+
+	if err := setModeVerified(client, manager, pending.Mode); err != nil {
+		return err
+	}
+	meta := manager.GetModeMetadata(modes.PowerMode(pending.Mode))
+	printOut(fmt.Sprintf("Reverted to %s\n", meta.Name))
+	if err := notifier.ShowModeChange(modeChangeMessage(meta, meta.Name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// smRemoteSession is the GetSystemMetrics index that is nonzero in a
+// Remote Desktop session
+const smRemoteSession = 0x1000
+
+var procGetSystemMetrics = windows.NewLazySystemDLL("user32.dll").NewProc("GetSystemMetrics")
+
+// sourceDetected marks a setting turned on because of the session the
+// helper runs in
+const sourceDetected = "detected"
+
+// safeModeSource records how safe mode was turned on, if it was
+var safeModeSource = sourceDefault
+
+// safeModeEnabled reports whether the helper should run without creating
+// any window: no toasts, HUD or tray, and no console attachment. It's on
+// with --safe-mode and, unless --safe-mode=false is given, in a Remote
+// Desktop session, where the OSD's layered windows are known to misbehave.
+// Like --no-console, the flag can appear anywhere and is removed from os.Args.
+func safeModeEnabled() bool {
+	enabled, explicit := false, false
+	args := os.Args[:1]
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--safe-mode", "-safe-mode", "--safe-mode=true", "-safe-mode=true":
+			enabled, explicit = true, true
+		case "--safe-mode=false", "-safe-mode=false":
+			enabled, explicit = false, true
+		default:
+			args = append(args, arg)
+		}
+	}
+	os.Args = args
+
+	if explicit {
+		if enabled {
+			safeModeSource = sourceFlag
+		}
+		return enabled
+	}
+
+	if remoteSession() {
+		safeModeSource = sourceDetected
+		fmt.Fprintln(os.Stderr, "Note: Remote Desktop session detected, running in safe mode: no toasts or windows (--safe-mode=false overrides)")
+		return true
+	}
+	return false
+}
+
+// remoteSession reports whether the helper runs in a Remote Desktop session
+func remoteSession() bool {
+	ret, _, _ := procGetSystemMetrics.Call(smRemoteSession)
+	return ret != 0
+}
+
+// windowCommands create windows of their own and so can't run in safe mode
+var windowCommands = map[string]bool{
+	"hud":      true,
+	"tray":     true,
+	"test-osd": true,
+}
@@ -0,0 +1,120 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handleSchedule applies the mode of the schedule rule active right now, if
+// any. It's meant to be run periodically (e.g. from Task Scheduler); `watch
+// --schedule` does the same continuously.
+func handleSchedule(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, rules []config.ScheduleRule) error {
+	if err := validateSchedule(manager, rules); err != nil {
+		return err
+	}
+
+	index, err := activeRule(rules, time.Now())
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		printOut("No schedule rule is active\n")
+		return nil
+	}
+
+	return applyScheduled(client, manager, notifier, rules[index])
+}
+
+// applyScheduled sets a rule's mode unless it's already in effect
+func applyScheduled(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, rule config.ScheduleRule) error {
+	current, err := client.GetCurrentMode()
+	if err != nil {
+		return err
+	}
+	if current == rule.Mode {
+		return nil
+	}
+
+	if err := setModeVerified(client, manager, rule.Mode); err != nil {
+		return err
+	}
+	printOut(fmt.Sprintf("Scheduled %s (%s)\n", rule.Mode, rule.Window))
+
+	meta := manager.GetModeMetadata(modes.PowerMode(rule.Mode))
+	if err := notifier.ShowModeChange(modeChangeMessage(meta, meta.Name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+	}
+	return nil
+}
+
+// validateSchedule checks every rule's window syntax and mode up front
+func validateSchedule(manager *modes.Manager, rules []config.ScheduleRule) error {
+	if len(rules) == 0 {
+		return fmt.Errorf("no schedule rules in the config file")
+	}
+	for _, rule := range rules {
+		if _, _, err := parseWindow(rule.Window); err != nil {
+			return err
+		}
+		if !manager.IsValidMode(rule.Mode) {
+			return fmt.Errorf("unknown power mode in schedule: %s", rule.Mode)
+		}
+	}
+	return nil
+}
+
+// activeRule returns the index of the first rule whose window contains now,
+// or -1 if none does
+func activeRule(rules []config.ScheduleRule, now time.Time) (int, error) {
+	minute := now.Hour()*60 + now.Minute()
+
+	for i, rule := range rules {
+		from, to, err := parseWindow(rule.Window)
+		if err != nil {
+			return -1, err
+		}
+
+		var inside bool
+		switch {
+		case from == to:
+			inside = true // a full day
+		case from < to:
+			inside = minute >= from && minute < to
+		default:
+			inside = minute >= from || minute < to // crosses midnight
+		}
+		if inside {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// parseWindow parses "HH:MM-HH:MM" into minutes since midnight
+func parseWindow(window string) (int, int, error) {
+	fromText, toText, ok := strings.Cut(window, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid schedule window '%s' (use HH:MM-HH:MM)", window)
+	}
+
+	from, err := time.Parse("15:04", strings.TrimSpace(fromText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid schedule window '%s' (use HH:MM-HH:MM)", window)
+	}
+	to, err := time.Parse("15:04", strings.TrimSpace(toText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid schedule window '%s' (use HH:MM-HH:MM)", window)
+	}
+
+	return from.Hour()*60 + from.Minute(), to.Hour()*60 + to.Minute(), nil
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// handleSensors prints CPU/GPU temperatures and fan speeds as a table, or as
+// a JSON object with --json
+func handleSensors(client *llt.Client, jsonOut bool) error {
+	sensors, err := client.GetSensors()
+	if err != nil {
+		return err
+	}
+
+	if jsonOut {
+		data, err := json.Marshal(sensors)
+		if err != nil {
+			return fmt.Errorf("failed to encode sensors: %w", err)
+		}
+		printOut(string(data) + "\n")
+		return nil
+	}
+
+	names := make([]string, 0, len(sensors))
+	width := 0
+	for name := range sensors {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%-*s  %s\n", width, name, sensors[name])
+	}
+	printOut(b.String())
+
+	return nil
+}
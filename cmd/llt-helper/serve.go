@@ -0,0 +1,276 @@
+//go:build windows
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/app"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/pipe"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// serveResponse is the reply to each request line on the pipe
+type serveResponse struct {
+	OK     bool   `json:"ok"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// serveRequest is a request sent as a JSON line rather than plain text,
+// e.g. {"command":"set","mode":"quiet"}
+type serveRequest struct {
+	Command string `json:"command"`
+	Mode    string `json:"mode,omitempty"`
+}
+
+// modeEvent is pushed to subscribed clients when the power mode changes
+type modeEvent struct {
+	Event    string    `json:"event"`
+	Mode     string    `json:"mode"`
+	Previous string    `json:"previous"`
+	Time     time.Time `json:"time"`
+}
+
+// pipeServer holds what serve's requests share: the same client, manager and
+// notifier the one-shot commands use, so answers match theirs
+type pipeServer struct {
+	// mu serializes the clients' use of the LLT client (which isn't safe for
+	// concurrent use), so two toggles never read the same starting mode
+	mu sync.Mutex
+
+	client       *llt.Client
+	manager      *modes.Manager
+	notifier     toast.Notifier
+	allowedModes []modes.PowerMode
+	interval     time.Duration
+	errSummary   *errorSummary
+}
+
+// handleServe answers line-based requests on a named pipe until the process
+// is stopped, serving each client on its own goroutine so a subscribed
+// plugin doesn't lock others out. Each response is a line of JSON.
+// toggle cycles through modesFlag, or the usual cycle when it's empty.
+func handleServe(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, modesFlag string, opts watchOptions) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	allowedModes, err := parseModesFlag(manager, modesFlag)
+	if err != nil {
+		return err
+	}
+
+	s := &pipeServer{
+		client:       client,
+		manager:      manager,
+		notifier:     notifier,
+		allowedModes: allowedModes,
+		interval:     opts.interval,
+		errSummary:   newErrorSummary(opts.errorSummary, notifier),
+	}
+	listener := pipe.Listen(pipe.DefaultName)
+	printOut(fmt.Sprintf("Listening on %s\n", pipe.DefaultName))
+
+	// On shutdown, disconnect the clients being served and free the pipe
+	var activeMu sync.Mutex
+	active := make(map[*pipe.Conn]bool)
+	onShutdown(func() {
+		activeMu.Lock()
+		defer activeMu.Unlock()
+		for conn := range active {
+			conn.Close()
+		}
+		clear(active)
+		listener.Close()
+	})
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		activeMu.Lock()
+		active[conn] = true
+		activeMu.Unlock()
+
+		go func() {
+			s.serveConn(conn)
+			activeMu.Lock()
+			defer activeMu.Unlock()
+			if active[conn] {
+				delete(active, conn)
+				conn.Close()
+			}
+		}()
+	}
+}
+
+// connWriter writes JSON lines to a client; the request loop and the
+// subscription push to the same connection
+type connWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (w *connWriter) encode(v any) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Encode(v)
+}
+
+// serveConn handles one client's requests until it disconnects. After
+// subscribe, mode changes are pushed from another goroutine while requests
+// keep being answered.
+func (s *pipeServer) serveConn(conn *pipe.Conn) {
+	scanner := bufio.NewScanner(conn)
+	w := &connWriter{enc: json.NewEncoder(conn)}
+	done := make(chan struct{})
+	defer close(done)
+	subscribed := false
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		request, err := parseServeRequest(line)
+		if err != nil {
+			w.encode(serveResponse{Error: err.Error()})
+			continue
+		}
+
+		if request.Command == "subscribe" {
+			if err := w.encode(serveResponse{OK: true}); err != nil {
+				return
+			}
+			if !subscribed {
+				subscribed = true
+				go s.subscribe(conn, w, done)
+			}
+			continue
+		}
+
+		s.mu.Lock()
+		result, err := s.handle(request)
+		s.mu.Unlock()
+		if err != nil {
+			w.encode(serveResponse{Error: err.Error()})
+			continue
+		}
+		w.encode(serveResponse{OK: true, Result: result})
+	}
+}
+
+// parseServeRequest reads a request line: plain text such as "set quiet",
+// or the same as JSON
+func parseServeRequest(line string) (serveRequest, error) {
+	if strings.HasPrefix(line, "{") {
+		var request serveRequest
+		if err := json.Unmarshal([]byte(line), &request); err != nil {
+			return serveRequest{}, fmt.Errorf("invalid request: %w", err)
+		}
+		return request, nil
+	}
+
+	command, mode, _ := strings.Cut(line, " ")
+	return serveRequest{Command: command, Mode: strings.TrimSpace(mode)}, nil
+}
+
+// handle answers a request other than subscribe the way the one-shot
+// command of the same name would
+func (s *pipeServer) handle(request serveRequest) (any, error) {
+	switch request.Command {
+	case "status":
+		return app.CurrentStatus(s.client, s.manager)
+	case "list":
+		return listModes(s.client, s.manager, false, orderLLT)
+	case "toggle":
+		return s.changeMode(func() (string, error) {
+			mode, err := cycleMode(s.client, s.manager, s.notifier, s.allowedModes, false, confirmOptions{})
+			return string(mode), err
+		})
+	case "set":
+		if request.Mode == "" {
+			return nil, fmt.Errorf("set needs a mode, e.g. set balance")
+		}
+		return s.changeMode(func() (string, error) {
+			opts := appOptions(s.manager, confirmOptions{})
+			opts.Changed = func(mode string) {
+				modeChanged(mode)
+				releaseInstance()
+			}
+			err := app.Set(s.client, s.manager, s.notifier, request.Mode, opts)
+			if errors.Is(err, errAlreadyActive) {
+				err = nil
+			}
+			return request.Mode, err
+		})
+	}
+	return nil, fmt.Errorf("unknown request: %s", request.Command)
+}
+
+// changeMode runs a toggle or set like the one-shot commands do: refused
+// while the mode is locked, and under the instance mutex so a button press
+// handled by another helper can't interleave with its read-modify-write.
+// The result describes the mode it landed on.
+func (s *pipeServer) changeMode(change func() (string, error)) (any, error) {
+	if locked := lockedMode(); locked != "" {
+		return nil, fmt.Errorf("power mode is locked to %s (run unlock)", locked)
+	}
+	if err := acquireInstance(singleInstanceWait); err != nil {
+		return nil, err
+	}
+	defer releaseInstance()
+
+	mode, err := change()
+	if err != nil {
+		return nil, err
+	}
+	return app.Describe(s.manager, mode), nil
+}
+
+// subscribe pushes a modeEvent whenever the power mode changes, polling at
+// the server's interval, until the client disconnects or done is closed
+func (s *pipeServer) subscribe(conn *pipe.Conn, w *connWriter, done <-chan struct{}) {
+	s.mu.Lock()
+	previous, _ := s.client.GetCurrentMode()
+	s.mu.Unlock()
+
+	for conn.Connected() {
+		select {
+		case <-done:
+			return
+		case <-time.After(s.interval):
+		}
+
+		s.mu.Lock()
+		s.errSummary.flush()
+		current, err := s.client.GetCurrentMode()
+		if err != nil {
+			s.errSummary.record()
+		}
+		s.mu.Unlock()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		if current == previous {
+			continue
+		}
+
+		event := modeEvent{Event: "mode-changed", Mode: current, Previous: previous, Time: time.Now()}
+		if err := w.encode(event); err != nil {
+			return
+		}
+		previous = current
+	}
+}
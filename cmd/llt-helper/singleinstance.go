@@ -0,0 +1,119 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// --single-instance policies
+const (
+	singleInstanceWait  = "wait"  // wait for the running helper, then continue
+	singleInstanceFail  = "fail"  // exit at once while another helper runs
+	singleInstanceAllow = "allow" // no guard
+)
+
+// instanceMutexName is held by a helper while it runs a command that changes
+// the laptop's settings
+const instanceMutexName = `Local\LLTHelperInstance`
+
+// instanceWaitTimeout bounds how long --single-instance=wait queues behind
+// another helper; a toggle, toast included, takes a few seconds at most
+const instanceWaitTimeout = 10 * time.Second
+
+// singleInstanceCommands change settings and so take the instance mutex.
+// Long-running commands (watch, serve, hud, schedule) don't, or they would
+// block every button press for as long as they run.
+var singleInstanceCommands = map[string]bool{
+	"toggle":       true,
+	"next":         true,
+	"set":          true,
+	"auto":         true,
+	"lock":         true,
+	"preset":       true,
+	"profile":      true,
+	"feature":      true,
+	"backlight":    true,
+	"refresh-rate": true,
+	"reset-state":  true,
+}
+
+var errAnotherInstance = errors.New("another llt-helper is already running")
+
+// instanceHandle is the instance mutex while this process holds it
+var instanceHandle windows.Handle
+
+// isValidSingleInstance reports whether policy is a --single-instance value
+func isValidSingleInstance(policy string) bool {
+	switch policy {
+	case singleInstanceWait, singleInstanceFail, singleInstanceAllow:
+		return true
+	}
+	return false
+}
+
+// acquireInstance takes the instance mutex according to policy, returning
+// errAnotherInstance if it's still held when policy gives up. Windows
+// releases it when the process exits (see releaseInstance for toggle), and a
+// holder that was killed leaves it abandoned, which the next helper takes
+// over.
+func acquireInstance(policy string) error {
+	if policy == singleInstanceAllow {
+		return nil
+	}
+
+	name, err := windows.UTF16PtrFromString(instanceMutexName)
+	if err != nil {
+		return fmt.Errorf("invalid instance mutex name: %w", err)
+	}
+
+	// ERROR_ALREADY_EXISTS still returns a usable handle
+	handle, err := windows.CreateMutex(nil, false, name)
+	if handle == 0 {
+		return fmt.Errorf("failed to create instance mutex: %w", err)
+	}
+
+	var timeout uint32
+	if policy == singleInstanceWait {
+		timeout = uint32(instanceWaitTimeout.Milliseconds())
+	}
+
+	// A mutex belongs to the thread that took it, and only that thread can
+	// release it early
+	runtime.LockOSThread()
+
+	event, err := windows.WaitForSingleObject(handle, timeout)
+	switch event {
+	case windows.WAIT_OBJECT_0, windows.WAIT_ABANDONED:
+		instanceHandle = handle
+		return nil
+	case uint32(windows.WAIT_TIMEOUT):
+		windows.CloseHandle(handle)
+		runtime.UnlockOSThread()
+		return errAnotherInstance
+	}
+	windows.CloseHandle(handle)
+	runtime.UnlockOSThread()
+	return fmt.Errorf("failed to wait for instance mutex: %w", err)
+}
+
+// releaseInstance hands the instance mutex to the next waiting helper before
+// this one exits. toggle calls it once the mode is set, so a second press
+// queued behind it reads the new mode and goes on while the first toast is
+// still showing, instead of waiting for that toast to close. It must run on
+// the goroutine that called acquireInstance. serve takes and releases the
+// mutex around each mode change.
+func releaseInstance() {
+	if instanceHandle == 0 {
+		return
+	}
+	windows.ReleaseMutex(instanceHandle)
+	windows.CloseHandle(instanceHandle)
+	instanceHandle = 0
+	runtime.UnlockOSThread()
+}
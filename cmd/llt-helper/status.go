@@ -0,0 +1,153 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/app"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// statusOptions holds the flags understood by the status command
+type statusOptions struct {
+	json      bool
+	short     bool
+	icon      bool
+	strict    bool
+	format    *template.Template // --format, already validated
+	write     statusFile
+	timestamp timestampOptions
+	verbose   bool
+	toast     bool
+}
+
+// errUnknownMode reports a power mode the helper has no metadata for
+var errUnknownMode = app.ErrUnknownMode
+
+// statusResult is the status command's machine-readable output
+type statusResult = app.Status
+
+// activeAutomation returns the LLT automation pipelines that can change the
+// power mode, or nil if there are none or LLT's automation can't be read
+func activeAutomation(client *llt.Client) []string {
+	names, err := client.PowerModeAutomations()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return names
+}
+
+// currentPowerSource returns onlyOnAC or onlyOnBattery, or "" when the
+// power source can't be determined
+func currentPowerSource() string {
+	source, err := powerSource()
+	if err != nil {
+		if !errors.Is(err, llt.ErrPowerSourceUnknown) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return ""
+	}
+	return source
+}
+
+// batteryPercent returns the battery charge level, or nil when there's no
+// battery or it can't be read
+func batteryPercent(client *llt.Client) *int {
+	percent, err := client.GetBatteryPercent()
+	if err != nil {
+		if !errors.Is(err, llt.ErrNoBattery) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return nil
+	}
+	return &percent
+}
+
+func handleStatus(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, opts statusOptions) error {
+	result, err := app.CurrentStatus(client, manager)
+	if err != nil {
+		return err
+	}
+	result.Timestamp = opts.timestamp.stamp(time.Now())
+	if opts.json || opts.verbose {
+		result.Automation = activeAutomation(client)
+		result.Battery = batteryPercent(client)
+		result.PowerSource = currentPowerSource()
+	}
+
+	// Known modes are the built-in cycle plus any configured in the config file
+	if opts.strict && !manager.IsValidMode(result.Mode) {
+		return fmt.Errorf("%w: LLT reported '%s'", errUnknownMode, result.Mode)
+	}
+
+	if err := opts.write.write(result); err != nil {
+		return err
+	}
+
+	switch {
+	case opts.format != nil:
+		var b strings.Builder
+		if err := opts.format.Execute(&b, result); err != nil {
+			return fmt.Errorf("failed to format status: %w", err)
+		}
+		out := b.String()
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		printOut(out)
+	case opts.json:
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode status: %w", err)
+		}
+		printOut(string(data) + "\n")
+	case opts.short:
+		printOut(result.Symbol + "\n")
+	case opts.icon:
+		printOut(result.Icon + "\n")
+	default:
+		printOut(opts.timestamp.prefix(time.Now(), fmt.Sprintf("Current Mode: %s (%s)\n", result.Name, result.Mode)))
+		if result.Battery != nil {
+			printOut(fmt.Sprintf("Battery: %d%%\n", *result.Battery))
+		}
+		if result.PowerSource != "" {
+			printOut(fmt.Sprintf("Power source: %s\n", powerSourceName(result.PowerSource)))
+		}
+		if len(result.Automation) > 0 {
+			printOut(fmt.Sprintf("LLT automation that may change it: %s\n", strings.Join(result.Automation, ", ")))
+		}
+	}
+
+	// Read-only feedback for buttons that have no console to print to
+	if opts.toast {
+		if err := notifier.Show(toast.CurrentModeTitle, toast.CurrentModeMessage(result.Name)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// parseStatusFormat parses a --format template for statusResult. The template
+// is also run once against an empty result, so a misspelled field is
+// reported up front rather than after LLT has been queried.
+func parseStatusFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, statusResult{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// defaultWriteFormat is the --write-format used when none is given
+const defaultWriteFormat = "{{.Name}}"
+
+// statusFile is the file --write keeps up to date with the current mode,
+// for overlays such as Rainmeter or OBS text sources to read
+type statusFile struct {
+	path   string
+	format *template.Template // --write-format, already validated
+}
+
+// write renders result with the file's format and replaces the file in one
+// step, so a reader never sees it half-written
+func (f statusFile) write(result statusResult) error {
+	if f.path == "" {
+		return nil
+	}
+
+	var b strings.Builder
+	if err := f.format.Execute(&b, result); err != nil {
+		return fmt.Errorf("failed to format status file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write status file %s: %w", f.path, err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write status file %s: %w", f.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write status file %s: %w", f.path, err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to write status file %s: %w", f.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinMode is the --mode value that reads the mode from stdin
+const stdinMode = "-"
+
+// maxStdinMode bounds how much of stdin is read for --mode=-; a mode name
+// is a few bytes, so anything longer isn't one
+const maxStdinMode = 256
+
+// readModeFromStdin reads the mode piped to `set --mode=-`: a single line,
+// trimmed. It fails on a console (nothing was piped), on empty input and
+// on more than one line.
+func readModeFromStdin() (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		return "", fmt.Errorf("--mode=- reads the mode from stdin, but nothing was piped in")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(os.Stdin, maxStdinMode+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read mode from stdin: %w", err)
+	}
+	if len(data) > maxStdinMode {
+		return "", fmt.Errorf("expected a single mode on stdin, got more than %d bytes", maxStdinMode)
+	}
+
+	// PowerShell pipes may start with a byte order mark
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(string(data), "\ufeff")), "\n")
+	if len(lines) > 1 {
+		return "", fmt.Errorf("expected a single mode on stdin, got %d lines", len(lines))
+	}
+	mode := strings.TrimSpace(lines[0])
+	if mode == "" {
+		return "", fmt.Errorf("no mode on stdin")
+	}
+	return mode, nil
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package main
+
+import "time"
+
+// timestampLayout is ISO 8601 with milliseconds, enough to order events
+// from a fast poll interval
+const timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// timestampOptions holds --with-timestamp and --local-time
+type timestampOptions struct {
+	enabled bool
+	local   bool // local time with its offset instead of UTC
+}
+
+// stamp formats t for output, or returns "" when timestamps are off
+func (o timestampOptions) stamp(t time.Time) string {
+	if !o.enabled {
+		return ""
+	}
+	if !o.local {
+		t = t.UTC()
+	}
+	return t.Format(timestampLayout)
+}
+
+// prefix puts the timestamp for t in front of a line of output
+func (o timestampOptions) prefix(t time.Time, line string) string {
+	if !o.enabled {
+		return line
+	}
+	return o.stamp(t) + " " + line
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// batteryNotifier adds the battery charge level as a second line to mode
+// change toasts, for --toast-show-battery. Without a battery the toast is
+// left as it is.
+type batteryNotifier struct {
+	toast.Notifier
+	client *llt.Client
+}
+
+func (n batteryNotifier) ShowModeChange(message, iconPath, color, position string) error {
+	if percent, err := n.client.GetBatteryPercent(); err == nil {
+		message = fmt.Sprintf("%s\nBattery %d%%", message, percent)
+	}
+	return n.Notifier.ShowModeChange(message, iconPath, color, position)
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/state"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// cooldownNotifier drops mode-change toasts shown less than cooldown after
+// the previous one, across invocations, so rapid changes don't flicker.
+// Error toasts are always shown.
+type cooldownNotifier struct {
+	toast.Notifier
+	cooldown time.Duration
+}
+
+func (n cooldownNotifier) ShowModeChange(message, iconPath, color, position string) error {
+	path := state.DefaultPath()
+	st, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if since := time.Since(st.LastToast); since >= 0 && since < n.cooldown {
+		return nil
+	}
+
+	st.LastToast = time.Now()
+	if err := st.Save(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return n.Notifier.ShowModeChange(message, iconPath, color, position)
+}
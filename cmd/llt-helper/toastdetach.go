@@ -0,0 +1,102 @@
+//go:build windows
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// detachedToastFlags are the toast flags passed on to the process that
+// shows a toast for --toast-wait=false
+var detachedToastFlags = []string{
+	"toast-multiline", "toast-animation", "toast-delay", "toast-position",
+	"toast-text-shadow", "toast-no-topmost", "toast-monitor", "toast-scale",
+	"toast-theme", "toast-progress", "toast-max-width", "toast-duration",
+}
+
+// showStacked shows one toast per line, collapsing lines beyond
+// toast.MaxStack into a final "+N more" toast. With a detachedNotifier the
+// toasts appear together as a stack; otherwise they follow one another.
+func showStacked(notifier toast.Notifier, title string, lines []string) {
+	if len(lines) > toast.MaxStack {
+		more := len(lines) - (toast.MaxStack - 1)
+		lines = append(lines[:toast.MaxStack-1:toast.MaxStack-1], fmt.Sprintf("+%d more", more))
+	}
+	for _, line := range lines {
+		if err := notifier.Show(title, line); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+		}
+	}
+}
+
+// detachedNotifier shows each toast from a separate, detached llt-helper
+// process (running test-osd), so the command can exit as soon as the mode is
+// set while the toast stays up for its usual time. The child process exits
+// when its toast closes.
+type detachedNotifier struct {
+	args []string // toast flags given to this process, passed on to the child
+}
+
+func newDetachedNotifier(fs *flag.FlagSet) detachedNotifier {
+	var args []string
+	fs.Visit(func(f *flag.Flag) {
+		if slices.Contains(detachedToastFlags, f.Name) {
+			args = append(args, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+	return detachedNotifier{args: args}
+}
+
+func (n detachedNotifier) ShowModeChange(message, iconPath, color, position string) error {
+	var extra []string
+	if iconPath != "" {
+		extra = append(extra, "--osd-icon="+iconPath)
+	}
+	if color != "" {
+		extra = append(extra, "--osd-color="+color)
+	}
+	if position != "" {
+		// Given last, so it overrides any --toast-position passed on
+		extra = append(extra, "--toast-position="+position)
+	}
+	return n.spawn(toast.ModeChangeTitle, message, extra...)
+}
+
+func (n detachedNotifier) ShowError(message string) error {
+	return n.spawn(toast.ErrorTitle, message)
+}
+
+func (n detachedNotifier) Show(title, message string) error {
+	return n.spawn(title, message)
+}
+
+// spawn starts the toast process without waiting for it
+func (n detachedNotifier) spawn(title, message string, extra ...string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to start toast process: %w", err)
+	}
+
+	// Detached toasts can overlap each other, so they always stack
+	args := append([]string{"test-osd", "--no-console", "--toast-stack", "--title=" + title, "--message=" + message}, n.args...)
+	args = append(args, configArgs()...)
+	cmd := exec.Command(exe, append(args, extra...)...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP,
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start toast process: %w", err)
+	}
+	return cmd.Process.Release()
+}
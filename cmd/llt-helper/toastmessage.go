@@ -0,0 +1,49 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// parseToastMessage parses a mode's toastMessage template, checking it
+// against empty metadata like parseStatusFormat does. An empty text gives
+// a nil template: the default message.
+func parseToastMessage(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("toastMessage").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, modes.ModeMetadata{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// modeChangeMessage returns the toast text for switching to a mode: its
+// toastMessage template or the default "Switched to X Mode". name replaces
+// meta.Name, so toggle can add the cycle position.
+func modeChangeMessage(meta modes.ModeMetadata, name string) string {
+	meta.Name = name
+	tmpl, err := parseToastMessage(meta.ToastMessage)
+	if err != nil || tmpl == nil {
+		return toast.ModeChangeMessage(name)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, meta); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toastMessage failed, using the default: %v\n", err)
+		return toast.ModeChangeMessage(name)
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// handleTray shows a notification area icon for the current mode, polling
+// like watch. Left-clicking it toggles (through the --modes cycle) and its
+// menu sets any known mode directly. It runs until Exit is chosen from the
+// menu or the process is stopped.
+func handleTray(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, modesFlag string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if _, err := parseModesFlag(manager, modesFlag); err != nil {
+		return err
+	}
+
+	// One change at a time; refresh wakes the poll loop to show its result
+	var mu sync.Mutex
+	refresh := make(chan struct{}, 1)
+	change := func(apply func() error) {
+		// Toasts pump window messages, so keep them on one OS thread
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		mu.Lock()
+		defer mu.Unlock()
+
+		var err error
+		if locked := lockedMode(); locked != "" {
+			err = fmt.Errorf("power mode is locked to %s (run unlock)", locked)
+		} else {
+			err = apply()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			if toastErr := notifier.ShowError(err.Error()); toastErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", toastErr)
+			}
+		}
+		select {
+		case refresh <- struct{}{}:
+		default:
+		}
+	}
+
+	var items []toast.TrayItem
+	for _, mode := range manager.Modes() {
+		items = append(items, toast.TrayItem{ID: string(mode), Label: manager.GetModeMetadata(mode).Name})
+	}
+
+	tray, err := toast.NewTray(items,
+		func() {
+			change(func() error {
+				return handleToggle(client, manager, notifier, modesFlag, false, confirmOptions{})
+			})
+		},
+		func(mode string) {
+			change(func() error {
+				if err := handleSet(client, manager, mode, notifier, confirmOptions{}, false); !errors.Is(err, errAlreadyActive) {
+					return err
+				}
+				return nil
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer tray.Close()
+	onShutdown(tray.Close)
+
+	current := ""
+	for {
+		mode, err := client.GetCurrentMode()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if mode != current {
+			current = mode
+			meta := manager.GetModeMetadata(modes.PowerMode(current))
+			tray.Update(fmt.Sprintf("Power Mode: %s", meta.Name), meta.IconPath, current)
+		}
+
+		select {
+		case <-tray.Done():
+			return nil
+		case <-refresh:
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,91 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// versionInfo is the --version output: the helper's own build details plus,
+// when it can be found, the installed LLT
+type versionInfo struct {
+	Version    string `json:"version"`
+	GoVersion  string `json:"goVersion,omitempty"`
+	Revision   string `json:"revision,omitempty"`
+	BuildTime  string `json:"buildTime,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+	LLTPath    string `json:"lltPath,omitempty"`
+	LLTVersion string `json:"lltVersion,omitempty"`
+}
+
+// collectVersionInfo gathers version details best-effort: anything that
+// can't be determined is simply left empty
+func collectVersionInfo() versionInfo {
+	info := versionInfo{Version: version}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = build.GoVersion
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.time":
+				info.BuildTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if client, err := llt.NewClient(); err == nil {
+		info.LLTPath = client.Path()
+		info.LLTVersion, _ = client.Version()
+	}
+
+	return info
+}
+
+// printVersion prints the --version output, as JSON when jsonOut is set
+func printVersion(jsonOut bool) {
+	info := collectVersionInfo()
+
+	if jsonOut {
+		data, err := json.Marshal(info)
+		if err == nil {
+			printOut(string(data) + "\n")
+			return
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "llt-helper version %s\n", info.Version)
+	if info.Revision != "" {
+		revision := info.Revision
+		if info.Modified {
+			revision += " (modified)"
+		}
+		fmt.Fprintf(&b, "  revision:    %s\n", revision)
+	}
+	if info.BuildTime != "" {
+		fmt.Fprintf(&b, "  built:       %s\n", info.BuildTime)
+	}
+	if info.GoVersion != "" {
+		fmt.Fprintf(&b, "  go:          %s\n", info.GoVersion)
+	}
+	if info.LLTPath != "" {
+		fmt.Fprintf(&b, "  LLT path:    %s\n", info.LLTPath)
+		lltVersion := info.LLTVersion
+		if lltVersion == "" {
+			lltVersion = "unknown"
+		}
+		fmt.Fprintf(&b, "  LLT version: %s\n", lltVersion)
+	} else {
+		fmt.Fprintf(&b, "  LLT:         not found\n")
+	}
+	printOut(b.String())
+}
@@ -0,0 +1,198 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/app"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/config"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/modes"
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// watchOptions holds the flags understood by the watch command
+type watchOptions struct {
+	interval       time.Duration
+	enforce        string
+	cooldown       time.Duration
+	toastOnEnforce bool
+	schedule       bool
+	rules          []config.ScheduleRule
+	errorSummary   time.Duration
+	write          statusFile
+	toastOnChange  bool
+	toastSource    string
+	timestamp      timestampOptions
+	features       []string // --features, printed as JSON events on change
+	json           bool     // print the status JSON on each mode change
+}
+
+// watchError is the JSON line watch --json prints when the mode can't be
+// read, once per outage
+type watchError struct {
+	Error     string `json:"error"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// handleWatch polls the current power mode until the process is stopped.
+// With --enforce, a drift away from the desired mode is corrected, at most
+// once per cooldown so the helper doesn't fight another app in a tight loop.
+// A mode pinned with `lock` takes precedence over the --enforce mode.
+// With --schedule, each schedule window's mode is applied once as it begins,
+// so a manual change inside the window sticks.
+// With --features, each listed feature is read every poll as well and a
+// JSON event is printed whenever one changes.
+// With --json, the mode is printed as status --json does, one line per
+// change (and once at start), so a plugin can follow it without spawning
+// status on a timer. While the mode can't be read (e.g. LLT was closed) an
+// error line is printed once and polling goes on; the mode is printed again
+// when LLT is back.
+func handleWatch(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, opts watchOptions) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if opts.enforce != "" && !manager.IsValidMode(opts.enforce) {
+		return fmt.Errorf("unknown power mode: %s", opts.enforce)
+	}
+	if opts.schedule {
+		if err := validateSchedule(manager, opts.rules); err != nil {
+			return err
+		}
+	}
+
+	var lastCorrection time.Time
+	var lastWritten, previous, lastPrinted string
+	var failing bool
+	lastRule := -1
+	errSummary := newErrorSummary(opts.errorSummary, notifier)
+	features := newFeatureWatcher(opts.features, opts.timestamp)
+	for {
+		if opts.schedule {
+			lastRule = applySchedule(client, manager, notifier, opts.rules, lastRule)
+		}
+		if err := completeOverdueRevert(client, manager, notifier); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+
+		target := opts.enforce
+		if locked := lockedMode(); target != "" && locked != "" {
+			target = locked
+		}
+
+		current, err := client.GetCurrentMode()
+		readAt := time.Now()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			errSummary.record()
+			if opts.json && !failing {
+				printJSONLine(watchError{Error: err.Error(), Timestamp: opts.timestamp.stamp(readAt)})
+			}
+			failing = true
+		} else {
+			if opts.json && (current != lastPrinted || failing) {
+				result := app.Describe(manager, current)
+				result.Timestamp = opts.timestamp.stamp(readAt)
+				printJSONLine(result)
+				lastPrinted = current
+			}
+			failing = false
+			if current != lastWritten {
+				result := app.Describe(manager, current)
+				result.Timestamp = opts.timestamp.stamp(readAt)
+				if err := opts.write.write(result); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				} else {
+					lastWritten = current
+				}
+			}
+			if features.has(powerModeFeature) {
+				features.changed(powerModeFeature, current, readAt)
+			}
+			if previous != "" && current != previous {
+				// --features reports the change as an event instead
+				if opts.timestamp.enabled && !opts.json && !features.has(powerModeFeature) {
+					printOut(opts.timestamp.prefix(readAt, fmt.Sprintf("Changed to %s (was %s)\n", current, previous)))
+				}
+				announceDetectedChange(manager, notifier, opts, current)
+			}
+			previous = current
+			if target != "" && current != target && time.Since(lastCorrection) >= opts.cooldown {
+				lastCorrection = time.Now()
+				enforceMode(client, manager, notifier, opts, target, current)
+			}
+		}
+		features.poll(client, errSummary)
+		errSummary.flush()
+
+		time.Sleep(opts.interval)
+	}
+}
+
+// printJSONLine prints v as one line of JSON. Stdout isn't buffered, so a
+// parent process reading the pipe gets each line as soon as it's printed.
+func printJSONLine(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to encode JSON: %v\n", err)
+		return
+	}
+	printOut(string(data) + "\n")
+}
+
+// applySchedule applies the active schedule rule when it differs from the
+// one applied last time, returning the rule now in effect
+func applySchedule(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, rules []config.ScheduleRule, lastRule int) int {
+	index, err := activeRule(rules, time.Now())
+	if err != nil || index == lastRule {
+		return lastRule
+	}
+
+	if index >= 0 {
+		if err := applyScheduled(client, manager, notifier, rules[index]); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			return lastRule // try again on the next poll
+		}
+	}
+	return index
+}
+
+// announceDetectedChange shows a toast for a mode change seen by watch, if
+// --toast-on-change is on and the change's origin passes --toast-source
+func announceDetectedChange(manager *modes.Manager, notifier toast.Notifier, opts watchOptions, mode string) {
+	if !opts.toastOnChange {
+		return
+	}
+	if opts.toastSource != toastSourceAll {
+		self := changedBySelf(mode, opts.interval+selfChangeSlack)
+		if self != (opts.toastSource == toastSourceSelf) {
+			return
+		}
+	}
+
+	meta := manager.GetModeMetadata(modes.PowerMode(mode))
+	if err := notifier.ShowModeChange(modeChangeMessage(meta, meta.Name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+	}
+}
+
+// enforceMode re-applies the target mode after a detected drift
+func enforceMode(client *llt.Client, manager *modes.Manager, notifier toast.Notifier, opts watchOptions, target, current string) {
+	if err := setModeVerified(client, manager, target); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+
+	printOut(opts.timestamp.prefix(time.Now(), fmt.Sprintf("Re-applied %s (was %s)\n", target, current)))
+
+	if opts.toastOnEnforce {
+		meta := manager.GetModeMetadata(modes.PowerMode(target))
+		if err := notifier.ShowModeChange(modeChangeMessage(meta, meta.Name), meta.IconPath, meta.Color, meta.ToastPosition); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: toast notification failed: %v\n", err)
+		}
+	}
+}
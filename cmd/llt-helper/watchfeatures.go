@@ -0,0 +1,122 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// powerModeFeature is the --features name for the mode watch always polls
+const powerModeFeature = "power-mode"
+
+// featureEvent is the JSON line watch --features prints for each change
+type featureEvent struct {
+	Feature   string `json:"feature"`
+	Value     string `json:"value"`
+	Previous  string `json:"previous,omitempty"` // empty for the first reading
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// parseWatchFeatures splits --features into feature names, checking each
+func parseWatchFeatures(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	var features []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if err := llt.ValidateFeatureName(name); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(features, name) {
+			features = append(features, name)
+		}
+	}
+	return features, nil
+}
+
+// featureWatcher tracks the features watch --features monitors besides the
+// power mode. A nil featureWatcher monitors nothing.
+type featureWatcher struct {
+	features  []string
+	last      map[string]string
+	failing   map[string]bool
+	timestamp timestampOptions
+}
+
+// newFeatureWatcher returns nil when no features are given
+func newFeatureWatcher(features []string, timestamp timestampOptions) *featureWatcher {
+	if len(features) == 0 {
+		return nil
+	}
+	return &featureWatcher{
+		features:  features,
+		last:      make(map[string]string),
+		failing:   make(map[string]bool),
+		timestamp: timestamp,
+	}
+}
+
+// has reports whether feature is monitored
+func (w *featureWatcher) has(feature string) bool {
+	return w != nil && slices.Contains(w.features, feature)
+}
+
+// poll reads each monitored feature except the power mode, which the watch
+// loop reads itself, and prints an event for each one that changed. A
+// feature that can't be read is reported once when it starts failing and
+// skipped until it recovers, so one unsupported feature doesn't end the loop
+// or flood the output.
+func (w *featureWatcher) poll(client *llt.Client, errSummary *errorSummary) {
+	if w == nil {
+		return
+	}
+
+	for _, feature := range w.features {
+		if feature == powerModeFeature {
+			continue
+		}
+
+		value, err := client.GetFeature(feature)
+		if err != nil {
+			if !w.failing[feature] {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				w.failing[feature] = true
+			}
+			errSummary.record()
+			continue
+		}
+		w.failing[feature] = false
+
+		w.changed(feature, value, time.Now())
+	}
+}
+
+// changed prints an event when value differs from the feature's last value
+func (w *featureWatcher) changed(feature, value string, readAt time.Time) {
+	previous, seen := w.last[feature]
+	if seen && value == previous {
+		return
+	}
+	w.last[feature] = value
+
+	data, err := json.Marshal(featureEvent{
+		Feature:   feature,
+		Value:     value,
+		Previous:  previous,
+		Timestamp: w.timestamp.stamp(readAt),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to encode %s event: %v\n", feature, err)
+		return
+	}
+	printOut(string(data) + "\n")
+}
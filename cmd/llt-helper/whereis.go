@@ -0,0 +1,64 @@
+//go:build windows
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/llt"
+)
+
+// whereisCandidate is one location checked for llt.exe
+type whereisCandidate struct {
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+// whereisReport is the whereis command's output
+type whereisReport struct {
+	Resolved   string             `json:"resolved,omitempty"`
+	Candidates []whereisCandidate `json:"candidates"`
+}
+
+// handleWhereis shows where llt.exe was looked for, in order, and which
+// location was used
+func handleWhereis(jsonOut bool) error {
+	report := whereisReport{Candidates: []whereisCandidate{}}
+	for _, path := range llt.CandidatePaths() {
+		_, err := os.Stat(path)
+		exists := err == nil
+		if exists && report.Resolved == "" {
+			report.Resolved = path
+		}
+		report.Candidates = append(report.Candidates, whereisCandidate{Path: path, Exists: exists})
+	}
+
+	if jsonOut {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		printOut(string(data) + "\n")
+		return nil
+	}
+
+	var b strings.Builder
+	if report.Resolved != "" {
+		fmt.Fprintf(&b, "LLT path: %s\n", report.Resolved)
+	} else {
+		fmt.Fprintf(&b, "LLT path: not found\n")
+	}
+	fmt.Fprintf(&b, "Checked, in order:\n")
+	for i, c := range report.Candidates {
+		status := "missing"
+		if c.Exists {
+			status = "found"
+		}
+		fmt.Fprintf(&b, "  %d. %s (%s)\n", i+1, c.Path, status)
+	}
+	printOut(b.String())
+	return nil
+}
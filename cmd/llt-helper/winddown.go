@@ -0,0 +1,51 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/cbrown350/streamdock-lenovo-legion-toolkit-helper/internal/toast"
+)
+
+// cleanups run, most recent first, when a long-running command is stopped
+var (
+	cleanupsMu sync.Mutex
+	cleanups   []func()
+)
+
+// onShutdown registers cleanup to run when the helper is stopped by
+// handleShutdown
+func onShutdown(cleanup func()) {
+	cleanupsMu.Lock()
+	defer cleanupsMu.Unlock()
+	cleanups = append(cleanups, cleanup)
+}
+
+// handleShutdown makes Ctrl+C, Ctrl+Break, closing the console window,
+// logoff, shutdown and SIGTERM wind the helper down: the registered
+// cleanups run (releasing the pipe and HUD), any OSD still showing is
+// closed, and the process exits 0, since being stopped is how watch, serve
+// and hud normally end. The Go runtime installs the SetConsoleCtrlHandler
+// handler that turns console control events into these signals, and it
+// holds off process termination on close/logoff/shutdown until we exit.
+func handleShutdown() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+
+		cleanupsMu.Lock()
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+		cleanupsMu.Unlock()
+
+		toast.CloseAll()
+		os.Exit(0)
+	}()
+}
@@ -0,0 +1,64 @@
+//go:build windows
+
+package broadcast
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Names other programs use to hear about mode changes made by the helper
+const (
+	// EventName is a manual-reset event that is pulsed after each change,
+	// releasing every thread waiting on it at that moment
+	EventName = `Local\LLTHelperModeChanged`
+
+	// MessageName is registered with RegisterWindowMessage; the message is
+	// posted to all top-level windows with wParam set to LLT's numeric
+	// index of the new mode (0 for custom modes) and lParam 0
+	MessageName = "LLTHelperModeChanged"
+)
+
+const hwndBroadcast = 0xffff
+
+var (
+	user32                     = windows.NewLazySystemDLL("user32.dll")
+	procRegisterWindowMessageW = user32.NewProc("RegisterWindowMessageW")
+	procPostMessageW           = user32.NewProc("PostMessageW")
+)
+
+// ModeChanged pulses EventName and broadcasts MessageName with the new
+// mode's index
+func ModeChanged(index int) error {
+	eventName, err := windows.UTF16PtrFromString(EventName)
+	if err != nil {
+		return fmt.Errorf("invalid event name: %w", err)
+	}
+	event, err := windows.CreateEvent(nil, 1, 0, eventName)
+	if err != nil {
+		return fmt.Errorf("failed to create event %s: %w", EventName, err)
+	}
+	defer windows.CloseHandle(event)
+
+	if err := windows.PulseEvent(event); err != nil {
+		return fmt.Errorf("failed to signal event %s: %w", EventName, err)
+	}
+
+	messageName, err := windows.UTF16PtrFromString(MessageName)
+	if err != nil {
+		return fmt.Errorf("invalid message name: %w", err)
+	}
+	msg, _, callErr := procRegisterWindowMessageW.Call(uintptr(unsafe.Pointer(messageName)))
+	if msg == 0 {
+		return fmt.Errorf("RegisterWindowMessage failed: %w", callErr)
+	}
+
+	// PostMessage rather than SendMessage, so a hung window can't block us
+	if ret, _, callErr := procPostMessageW.Call(hwndBroadcast, msg, uintptr(index), 0); ret == 0 {
+		return fmt.Errorf("failed to broadcast %s: %w", MessageName, callErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultPresetFeatures are the LLT features captured by `preset save` when
+// the config doesn't list its own
+var DefaultPresetFeatures = []string{"battery", "white-keyboard-backlight"}
+
+// Preset is a named snapshot of the power mode and LLT feature values
+type Preset struct {
+	PowerMode string            `json:"powerMode,omitempty"`
+	Features  map[string]string `json:"features,omitempty"`
+}
+
+// ScheduleRule applies a power mode during a daily time window
+type ScheduleRule struct {
+	Window string `json:"window"` // "HH:MM-HH:MM" local time, may cross midnight (e.g. "22:00-07:00")
+	Mode   string `json:"mode"`
+}
+
+// ModeConfig overrides display metadata for a power mode. Any field left
+// empty keeps the built-in value.
+type ModeConfig struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Icon        string `json:"icon,omitempty"` // absolute, or relative to the install directory
+	Color       string `json:"color,omitempty"`
+	Symbol      string `json:"symbol,omitempty"` // shown by status --short
+
+	// ToastPosition overrides --toast-position for this mode's toasts
+	ToastPosition string `json:"toastPosition,omitempty"`
+
+	// ToastMessage replaces "Switched to {{.Name}} Mode" in this mode's
+	// toasts; a Go template over Name, Description, Symbol and Color
+	ToastMessage string `json:"toastMessage,omitempty"`
+}
+
+// Config holds user settings stored in the helper's config file
+type Config struct {
+	// Modes maps a power mode id (e.g. "godmode") to its display metadata
+	Modes map[string]ModeConfig `json:"modes,omitempty"`
+
+	// Aliases maps a command word to the arguments it expands to
+	Aliases map[string][]string `json:"aliases,omitempty"`
+
+	// DefaultCommand is run when the helper is started without arguments,
+	// e.g. ["toggle"]
+	DefaultCommand []string `json:"defaultCommand,omitempty"`
+
+	// PresetFeatures lists the LLT features captured by `preset save`
+	PresetFeatures []string          `json:"presetFeatures,omitempty"`
+	Presets        map[string]Preset `json:"presets,omitempty"`
+
+	// Schedule lists time windows and their modes; the first matching rule wins
+	Schedule []ScheduleRule `json:"schedule,omitempty"`
+
+	// ModeMap maps power mode names as LLT prints them to canonical mode ids
+	// (e.g. "Turbo": "performance"), matched case-insensitively
+	ModeMap map[string]string `json:"modeMap,omitempty"`
+
+	// Sequence is the default toggle cycle, e.g. ["quiet", "performance"];
+	// --modes overrides it
+	Sequence []string `json:"sequence,omitempty"`
+
+	// SequenceAC and SequenceBattery replace Sequence for toggle and next
+	// on that power source; --modes-ac and --modes-battery override them
+	SequenceAC      []string `json:"sequenceAC,omitempty"`
+	SequenceBattery []string `json:"sequenceBattery,omitempty"`
+
+	// OSDDurationMs is how long toasts stay up, in milliseconds;
+	// --toast-duration overrides it
+	OSDDurationMs int `json:"osdDurationMs,omitempty"`
+
+	// Toast set to false turns toasts off unless --no-toast=false is given
+	Toast *bool `json:"toast,omitempty"`
+
+	// ToastWait set to false makes commands exit once the mode is set,
+	// leaving the toast to a detached process, unless --toast-wait is given
+	ToastWait *bool `json:"toastWait,omitempty"`
+}
+
+// DefaultPath returns the default config file location (%APPDATA%\llt-helper\config.json)
+func DefaultPath() string {
+	base := os.Getenv("APPDATA")
+	if base == "" {
+		base = filepath.Join(os.Getenv("USERPROFILE"), "AppData", "Roaming")
+	}
+	return filepath.Join(base, "llt-helper", "config.json")
+}
+
+// Load reads the config file at path. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return &Config{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Save writes the config to path, creating the parent directory if needed.
+// It writes a temporary file next to path and renames it into place, so a
+// failed write leaves the existing config untouched.
+func (c *Config) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// GetPresetFeatures returns the features to capture when saving a preset
+func (c *Config) GetPresetFeatures() []string {
+	if len(c.PresetFeatures) > 0 {
+		return c.PresetFeatures
+	}
+	return DefaultPresetFeatures
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "llt-helper", "config.json")
+	cfg := &Config{Presets: map[string]Preset{"work": {PowerMode: "quiet"}}}
+
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Presets["work"].PowerMode != "quiet" {
+		t.Errorf("loaded presets = %v, want the saved one", loaded.Presets)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("config directory holds %d files, want only config.json (no temporary file left)", len(entries))
+	}
+}
+
+func TestSaveReplacesConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"sequence": ["quiet", "performance"], "aliases": {"p": ["preset"]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (&Config{Sequence: []string{"balance"}}).Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Sequence) != 1 || loaded.Sequence[0] != "balance" || len(loaded.Aliases) != 0 {
+		t.Errorf("loaded %+v, want only the saved config", loaded)
+	}
+}
+
+func TestLoadMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"sequence": [`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "failed to parse config") {
+		t.Errorf("Load error = %v, want a parse error", err)
+	}
+	if cfg == nil {
+		t.Error("Load returned a nil config, want defaults")
+	}
+}
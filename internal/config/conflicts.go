@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// featureSetting matches a preset feature set to a value. Names and values
+// are compared after normalizeSetting, so "RapidCharge" matches "rapid-charge".
+type featureSetting struct {
+	feature, value string
+}
+
+// featureConflict is a pair of settings LLT can't apply together: the one
+// applied second fails or silently undoes the first
+type featureConflict struct {
+	first, second []featureSetting
+	reason        string
+}
+
+// featureConflicts are the known mutually exclusive preset settings. Each
+// side lists the spellings used by different LLT releases.
+var featureConflicts = []featureConflict{
+	{
+		first:  []featureSetting{{"battery", "conservation"}, {"conservation-mode", "on"}},
+		second: []featureSetting{{"battery", "rapid-charge"}, {"rapid-charge", "on"}},
+		reason: "battery conservation and rapid charge are mutually exclusive",
+	},
+}
+
+// Conflicts lists the preset's features that can't be applied together,
+// one line per conflict, including the same feature given twice with
+// different casing. An empty result means the preset is consistent.
+func (p Preset) Conflicts() []string {
+	features := make([]string, 0, len(p.Features))
+	for feature := range p.Features {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+
+	var conflicts []string
+	for i, a := range features {
+		for _, b := range features[i+1:] {
+			if strings.EqualFold(a, b) && p.Features[a] != p.Features[b] {
+				conflicts = append(conflicts, fmt.Sprintf("%s=%s conflicts with %s=%s: the same feature is set twice", a, p.Features[a], b, p.Features[b]))
+			}
+		}
+	}
+
+	for _, conflict := range featureConflicts {
+		first, ok := p.matchSetting(features, conflict.first)
+		if !ok {
+			continue
+		}
+		second, ok := p.matchSetting(features, conflict.second)
+		if !ok || first == second {
+			continue
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s=%s conflicts with %s=%s: %s", first, p.Features[first], second, p.Features[second], conflict.reason))
+	}
+
+	return conflicts
+}
+
+// matchSetting returns the first of features (the preset's, sorted) that
+// is set to any of settings
+func (p Preset) matchSetting(features []string, settings []featureSetting) (string, bool) {
+	for _, feature := range features {
+		for _, setting := range settings {
+			if normalizeSetting(feature) == normalizeSetting(setting.feature) && normalizeSetting(p.Features[feature]) == normalizeSetting(setting.value) {
+				return feature, true
+			}
+		}
+	}
+	return "", false
+}
+
+// normalizeSetting lowercases s and drops separators, since LLT releases
+// differ in how they spell feature names and values
+func normalizeSetting(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ExportVersion is the format version written by Export. Import accepts
+// files from newer versions, reporting the fields it doesn't know.
+const ExportVersion = 1
+
+// exportFile is the portable JSON written by `config export`
+type exportFile struct {
+	Version int    `json:"version"`
+	Config  Config `json:"config"`
+}
+
+// Export writes the config to path in the portable export format
+func (c *Config) Export(path string) error {
+	data, err := json.MarshalIndent(exportFile{Version: ExportVersion, Config: *c}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write export %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Import reads a file written by Export. Fields the config doesn't have
+// (e.g. added by a newer helper) are skipped and returned by name so the
+// caller can report them.
+func Import(path string) (*Config, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read export %s: %w", path, err)
+	}
+
+	var raw struct {
+		Version int                        `json:"version"`
+		Config  map[string]json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse export %s: %w", path, err)
+	}
+	if raw.Version < 1 || raw.Config == nil {
+		return nil, nil, fmt.Errorf("%s is not a config export (missing version or config)", path)
+	}
+
+	var file exportFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse export %s: %w", path, err)
+	}
+
+	known := jsonFields(reflect.TypeOf(Config{}))
+	var skipped []string
+	for name := range raw.Config {
+		// encoding/json matches field names case-insensitively
+		if !known[strings.ToLower(name)] {
+			skipped = append(skipped, name)
+		}
+	}
+	sort.Strings(skipped)
+
+	return &file.Config, skipped, nil
+}
+
+// jsonFields returns the lowercased JSON names of a struct's fields
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		fields[strings.ToLower(name)] = true
+	}
+	return fields
+}
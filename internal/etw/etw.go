@@ -0,0 +1,88 @@
+//go:build windows
+
+package etw
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// ProviderGUID identifies the helper's ETW provider when starting a trace
+// session ({1d160d36-72b3-45a6-bb2d-44bba3f11599}). Events are plain strings
+// from a manifest-less provider, so standard tools such as logman capture
+// them without anything being installed.
+var ProviderGUID = windows.GUID{
+	Data1: 0x1d160d36,
+	Data2: 0x72b3,
+	Data3: 0x45a6,
+	Data4: [8]byte{0xbb, 0x2d, 0x44, 0xbb, 0xa3, 0xf1, 0x15, 0x99},
+}
+
+// Level is an ETW event level; trace sessions can filter on it
+type Level uint8
+
+const (
+	LevelError   Level = 2
+	LevelInfo    Level = 4
+	LevelVerbose Level = 5
+)
+
+var (
+	advapi32             = windows.NewLazySystemDLL("advapi32.dll")
+	procEventRegister    = advapi32.NewProc("EventRegister")
+	procEventUnregister  = advapi32.NewProc("EventUnregister")
+	procEventWriteString = advapi32.NewProc("EventWriteString")
+)
+
+// Provider is a registered ETW provider. A nil Provider writes nothing, so
+// callers needn't check whether --etw was given.
+type Provider struct {
+	handle uint64 // REGHANDLE
+}
+
+// Register registers the provider. Writing costs next to nothing while no
+// trace session has enabled it.
+func Register() (*Provider, error) {
+	// A REGHANDLE is 64 bits and is passed by value, which a single
+	// syscall argument only holds on 64-bit Windows
+	if unsafe.Sizeof(uintptr(0)) < 8 {
+		return nil, errors.New("ETW events need a 64-bit build")
+	}
+
+	var p Provider
+	ret, _, _ := procEventRegister.Call(
+		uintptr(unsafe.Pointer(&ProviderGUID)),
+		0,
+		0,
+		uintptr(unsafe.Pointer(&p.handle)),
+	)
+	if ret != 0 {
+		return nil, fmt.Errorf("EventRegister failed: %w", windows.Errno(ret))
+	}
+	return &p, nil
+}
+
+// Write writes one event at level. Failures (e.g. a full trace buffer) are
+// ignored: tracing must never affect the command being traced.
+func (p *Provider) Write(level Level, message string) {
+	if p == nil {
+		return
+	}
+	text, err := windows.UTF16PtrFromString(message)
+	if err != nil {
+		return
+	}
+	procEventWriteString.Call(uintptr(p.handle), uintptr(level), 0, uintptr(unsafe.Pointer(text)))
+}
+
+// Close unregisters the provider
+func (p *Provider) Close() {
+	if p == nil {
+		return
+	}
+	procEventUnregister.Call(uintptr(p.handle))
+	p.handle = 0
+}
@@ -0,0 +1,43 @@
+package llt
+
+import (
+	"debug/pe"
+	"fmt"
+)
+
+// ArchAnyCPU is reported for 32-bit .NET images, which run as 64-bit
+// processes on 64-bit Windows unless they are marked 32-bit preferred
+const ArchAnyCPU = "anycpu"
+
+// Architecture returns the CPU architecture llt.exe is built for, read from
+// its PE header, using GOARCH names ("amd64", "386", "arm64") or ArchAnyCPU
+func (c *Client) Architecture() (string, error) {
+	f, err := pe.Open(c.lltPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read LLT architecture: %w", err)
+	}
+	defer f.Close()
+
+	switch f.Machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "amd64", nil
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64", nil
+	case pe.IMAGE_FILE_MACHINE_I386:
+		if isManaged(f) {
+			return ArchAnyCPU, nil
+		}
+		return "386", nil
+	}
+	return "", fmt.Errorf("unknown LLT machine type 0x%x", f.Machine)
+}
+
+// isManaged reports whether a 32-bit PE image has a CLR header, i.e. is a
+// .NET assembly
+func isManaged(f *pe.File) bool {
+	header, ok := f.OptionalHeader.(*pe.OptionalHeader32)
+	if !ok || header.NumberOfRvaAndSizes <= pe.IMAGE_DIRECTORY_ENTRY_COM_DESCRIPTOR {
+		return false
+	}
+	return header.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_COM_DESCRIPTOR].VirtualAddress != 0
+}
@@ -0,0 +1,101 @@
+package llt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LLT keeps its automation pipelines in automation.json next to
+// settings.json. Pipelines with a trigger (AC connected, app started, ...)
+// run on their own; those without one are the Quick Actions that
+// `quickAction` lists and runs. The CLI can't report which automation is
+// in effect, so the file is read directly.
+
+// automationStore is the part of automation.json the helper reads
+type automationStore struct {
+	IsEnabled bool `json:"IsEnabled"`
+	Pipelines []struct {
+		Name    *string                      `json:"Name"`
+		Trigger map[string]json.RawMessage   `json:"Trigger"`
+		Steps   []map[string]json.RawMessage `json:"Steps"`
+	} `json:"Pipelines"`
+}
+
+// PowerModeAutomations returns the LLT automation pipelines that can change
+// the power mode on their own: enabled, triggered, and with a power mode
+// step. Unnamed pipelines are reported by their trigger type. It returns
+// nil, without an error, when LLT has no automation file.
+func (c *Client) PowerModeAutomations() ([]string, error) {
+	for _, dir := range []string{filepath.Dir(c.lltPath), filepath.Dir(SettingsPath())} {
+		names, err := powerModeAutomations(filepath.Join(dir, "automation.json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return names, err
+	}
+	return nil, nil
+}
+
+// powerModeAutomations reads one automation file for PowerModeAutomations
+func powerModeAutomations(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var store automationStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse LLT automation %s: %w", path, err)
+	}
+	if !store.IsEnabled {
+		return nil, nil
+	}
+
+	var names []string
+	for _, pipeline := range store.Pipelines {
+		if pipeline.Trigger == nil || !hasPowerModeStep(pipeline.Steps) {
+			continue
+		}
+		if pipeline.Name != nil && *pipeline.Name != "" {
+			names = append(names, *pipeline.Name)
+		} else {
+			names = append(names, triggerName(pipeline.Trigger))
+		}
+	}
+	return names, nil
+}
+
+// hasPowerModeStep reports whether a pipeline sets the power mode
+func hasPowerModeStep(steps []map[string]json.RawMessage) bool {
+	for _, step := range steps {
+		if strings.Contains(typeName(step), "PowerModeAutomationStep") {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerName turns a trigger's .NET type, e.g.
+// "LenovoLegionToolkit.Lib.Automation.Pipeline.Triggers.ACAdapterConnectedAutomationPipelineTrigger, LenovoLegionToolkit.Lib",
+// into a short label such as "ACAdapterConnected"
+func triggerName(trigger map[string]json.RawMessage) string {
+	name, _, _ := strings.Cut(typeName(trigger), ",")
+	name = name[strings.LastIndex(name, ".")+1:]
+	name = strings.TrimSuffix(name, "AutomationPipelineTrigger")
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
+
+// typeName returns the "$type" Json.NET records for a polymorphic object
+func typeName(object map[string]json.RawMessage) string {
+	var name string
+	json.Unmarshal(object["$type"], &name)
+	return name
+}
@@ -0,0 +1,29 @@
+package llt
+
+import "fmt"
+
+// KeyboardBacklightFeature is LLT's feature for the white keyboard backlight
+const KeyboardBacklightFeature = "white-keyboard-backlight"
+
+// GetKeyboardBacklight returns the current keyboard backlight level (e.g. "Low")
+func (c *Client) GetKeyboardBacklight() (string, error) {
+	return c.GetFeature(KeyboardBacklightFeature)
+}
+
+// SetKeyboardBacklight sets the keyboard backlight level
+func (c *Client) SetKeyboardBacklight(level string) error {
+	return c.SetFeature(KeyboardBacklightFeature, level)
+}
+
+// KeyboardBacklightLevels returns the levels this device's backlight
+// supports, in LLT's order (e.g. Off, Low, High, or just Off, On)
+func (c *Client) KeyboardBacklightLevels() ([]string, error) {
+	levels, err := c.listFeatureValues(KeyboardBacklightFeature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keyboard backlight levels: %w", err)
+	}
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%w: no keyboard backlight levels reported", ErrFeatureUnsupported)
+	}
+	return levels, nil
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package llt
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procGetSystemPowerStatus = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetSystemPowerStatus")
+
+// systemPowerStatus mirrors SYSTEM_POWER_STATUS
+type systemPowerStatus struct {
+	ACLineStatus        byte // 0 offline, 1 online, 255 unknown
+	BatteryFlag         byte // 128 no system battery, 255 unknown
+	BatteryLifePercent  byte // 0-100, 255 unknown
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+// powerStatus reads the system power status from Windows
+func powerStatus() (systemPowerStatus, error) {
+	var status systemPowerStatus
+	if ret, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status))); ret == 0 {
+		return status, fmt.Errorf("failed to read power status: %w", err)
+	}
+	return status, nil
+}
+
+// OnACPower reports whether the laptop is plugged in
+func OnACPower() (bool, error) {
+	status, err := powerStatus()
+	if err != nil {
+		return false, err
+	}
+
+	switch status.ACLineStatus {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, ErrPowerSourceUnknown
+}
+
+// GetBatteryPercent returns the battery charge level (0-100). It comes from
+// Windows rather than llt.exe, so it costs no process spawn; ErrNoBattery
+// means there's no battery to report.
+func (c *Client) GetBatteryPercent() (int, error) {
+	status, err := powerStatus()
+	if err != nil {
+		return 0, err
+	}
+	if status.BatteryFlag == 128 || status.BatteryFlag == 255 || status.BatteryLifePercent > 100 {
+		return 0, ErrNoBattery
+	}
+	return int(status.BatteryLifePercent), nil
+}
@@ -2,85 +2,521 @@ package llt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
-	"syscall"
+	"sync"
 	"time"
+	"unicode"
 )
 
 // Client wraps interactions with Lenovo Legion Toolkit CLI
 type Client struct {
 	lltPath string
+
+	// equalsSyntax is set once LLT has rejected `f set NAME VALUE` but
+	// accepted `f set NAME=VALUE`, so later calls go straight to that form
+	equalsSyntax bool
+
+	calls []Call
+
+	// Trace, when set, is called after every llt.exe invocation
+	Trace func(Call)
+
+	// Timeout bounds each llt.exe invocation; zero means DefaultTimeout
+	Timeout time.Duration
+
+	// MaxRate caps how many llt.exe processes are started per second; calls
+	// over the rate wait for their turn (within Timeout). Zero means no limit.
+	MaxRate float64
+
+	// Retries is how many times an llt.exe invocation that failed for no
+	// known reason is retried, all within Timeout; zero means
+	// DefaultRetries and a negative value disables retrying
+	Retries int
+
+	// RetryDelay is the wait before the first retry, doubling after each
+	// one; zero means DefaultRetryDelay
+	RetryDelay time.Duration
+
+	limiterOnce sync.Once
+	rateLimiter *rateLimiter
+
+	// ReadSource selects how GetCurrentMode reads the mode (ReadSourceCLI,
+	// ReadSourceWMI or ReadSourceAuto); empty means ReadSourceCLI
+	ReadSource string
+
+	// CacheMode makes GetCurrentMode read the mode at most once and reuse it
+	// until the client changes a setting, so a command that checks the mode
+	// in several steps starts llt.exe once. Long-running commands leave it
+	// off to see changes made elsewhere.
+	CacheMode bool
+
+	// cachedMode is the mode last read while CacheMode is on, or empty
+	cachedMode string
+
+	// ExtraArgs are appended verbatim to every `f get` and `f set` invocation.
+	// They are for experimenting with LLT flags the helper doesn't wrap and
+	// are not validated.
+	ExtraArgs []string
+
+	// ExitCodes maps llt.exe exit codes to typed errors; nil means
+	// DefaultExitCodes
+	ExitCodes map[int]error
+
+	// ModeMap translates power mode names as LLT prints them (lowercase
+	// keys) to canonical ids, for builds whose names the built-in
+	// translations don't cover
+	ModeMap map[string]string
+
+	// Runner starts llt.exe; nil runs the real executable (see CommandRunner)
+	Runner CommandRunner
+}
+
+// busyRetries and busyDelay bound how long a busy LLT is waited for
+const (
+	busyRetries = 3
+	busyDelay   = 300 * time.Millisecond
+)
+
+// updateRetries and updateDelay bound how long an llt.exe being replaced by
+// an LLT update is waited for; updates swap the file within a few seconds
+const (
+	updateRetries = 2
+	updateDelay   = time.Second
+)
+
+// DefaultTimeout is how long an llt.exe invocation may take unless Client.Timeout is set
+const DefaultTimeout = 5 * time.Second
+
+// DefaultRetries and DefaultRetryDelay are used unless Client.Retries and
+// Client.RetryDelay are set
+const (
+	DefaultRetries    = 2
+	DefaultRetryDelay = 200 * time.Millisecond
+)
+
+// Call records a single llt.exe invocation and how long it took
+type Call struct {
+	Args     []string
+	Duration time.Duration
+	Err      error
+	// Output is what llt.exe printed, trimmed
+	Output string
 }
 
-// NewClient creates a new LLT client and auto-detects the LLT path
+// PathEnv is the environment variable that points NewClient at an llt.exe
+// outside the usual install locations
+const PathEnv = "LLT_PATH"
+
+// NewClient creates a new LLT client for the llt.exe named by LLT_PATH or,
+// when that isn't set, auto-detects the LLT path from CandidatePaths, using
+// the first one that exists. It returns ErrLLTNotExecutable if that llt.exe
+// can't be opened.
 func NewClient() (*Client, error) {
-	lltPath := os.Getenv("LOCALAPPDATA")
-	if lltPath == "" {
-		lltPath = filepath.Join(os.Getenv("USERPROFILE"), "AppData", "Local")
+	if path := os.Getenv(PathEnv); path != "" {
+		return NewClientAt(path)
+	}
+	if err := checkPlatform(); err != nil {
+		return nil, err
+	}
+	candidates := CandidatePaths()
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			if err := probeExecutable(path); err != nil {
+				return nil, err
+			}
+			return &Client{lltPath: path}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w at %s", ErrLLTNotFound, strings.Join(candidates, " or "))
+}
+
+// waitPollInterval is how often NewClientWait checks for LLT
+const waitPollInterval = 500 * time.Millisecond
+
+// NewClientWait is like NewClient but keeps retrying until timeout while LLT
+// isn't found or its CLI isn't responding yet (e.g. right after login). If
+// LLT is installed but still not responding at the deadline, the client is
+// returned anyway so the caller's own IsRunning check reports it.
+func NewClientWait(timeout time.Duration) (*Client, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		client, err := NewClient()
+		if err == nil && client.IsRunning() {
+			return client, nil
+		}
+		// Waiting won't unblock an llt.exe that antivirus or permissions
+		// stop, nor bring LLT to another platform
+		if errors.Is(err, ErrLLTNotExecutable) || errors.Is(err, ErrUnsupportedPlatform) || time.Now().After(deadline) {
+			return client, err
+		}
+		time.Sleep(waitPollInterval)
+	}
+}
+
+// NewClientAt creates a client for the llt.exe at path, e.g. one installed
+// to a custom directory. Unlike NewClient it doesn't look anywhere else.
+func NewClientAt(path string) (*Client, error) {
+	if err := checkPlatform(); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w at %s", ErrLLTNotFound, path)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("LLT path %s is a directory, not llt.exe", path)
+	}
+	if err := probeExecutable(path); err != nil {
+		return nil, err
 	}
-	lltPath = filepath.Join(lltPath, "Programs", "LenovoLegionToolkit", "llt.exe")
+
+	return &Client{lltPath: path}, nil
+}
+
+// CandidatePaths lists where NewClient looks for llt.exe, in order: the
+// per-user install under %LOCALAPPDATA% (or its usual location under
+// %USERPROFILE% when that isn't set), then a machine-wide install. With
+// LLT_PATH set, that is the only candidate.
+func CandidatePaths() []string {
+	if path := os.Getenv(PathEnv); path != "" {
+		return []string{path}
+	}
+
+	var candidates []string
+	add := func(base string) {
+		if base == "" {
+			return
+		}
+		path := lltPathFromBase(base)
+		for _, existing := range candidates {
+			if strings.EqualFold(existing, path) {
+				return
+			}
+		}
+		candidates = append(candidates, path)
+	}
+
+	add(os.Getenv("LOCALAPPDATA"))
+	if profile := os.Getenv("USERPROFILE"); profile != "" {
+		add(filepath.Join(profile, "AppData", "Local"))
+	}
+	if programFiles := os.Getenv("ProgramFiles"); programFiles != "" {
+		candidates = append(candidates, filepath.Join(programFiles, "LenovoLegionToolkit", "llt.exe"))
+	}
+
+	return candidates
+}
+
+// NewClientFromBase creates a client for the LLT installed under base, which
+// is normally %LOCALAPPDATA%. Tests can pass a temp dir with a fake llt.exe.
+func NewClientFromBase(base string) (*Client, error) {
+	if err := checkPlatform(); err != nil {
+		return nil, err
+	}
+	lltPath := lltPathFromBase(base)
 
 	if _, err := os.Stat(lltPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("LLT not found at %s", lltPath)
+		return nil, fmt.Errorf("%w at %s", ErrLLTNotFound, lltPath)
+	}
+	if err := probeExecutable(lltPath); err != nil {
+		return nil, err
 	}
 
 	return &Client{lltPath: lltPath}, nil
 }
 
+// lltPathFromBase returns where the LLT installer places llt.exe under base
+func lltPathFromBase(base string) string {
+	return filepath.Join(base, "Programs", "LenovoLegionToolkit", "llt.exe")
+}
+
+// command builds an llt.exe invocation with its console window hidden,
+// run from the LLT install directory since some LLT builds resolve their
+// resources against the working directory
+func (c *Client) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, c.lltPath, args...)
+	cmd.Dir = filepath.Dir(c.lltPath)
+	hideWindow(cmd)
+	return cmd
+}
+
+// context returns a context bounded by the client's timeout
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout())
+}
+
+// retries returns how many times a failed invocation may be retried
+func (c *Client) retries() int {
+	switch {
+	case c.Retries < 0:
+		return 0
+	case c.Retries == 0:
+		return DefaultRetries
+	}
+	return c.Retries
+}
+
+// retryDelay returns the wait before the given retry (counted from zero)
+func (c *Client) retryDelay(retry int) time.Duration {
+	delay := c.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	return delay << retry
+}
+
+// timeout returns the per-invocation timeout
+func (c *Client) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return DefaultTimeout
+}
+
+// output runs llt.exe and returns its stdout, recording the call
+func (c *Client) output(ctx context.Context, args ...string) ([]byte, error) {
+	return c.run(ctx, args, c.runner(false))
+}
+
+// combinedOutput runs llt.exe and returns stdout and stderr, recording the call
+func (c *Client) combinedOutput(ctx context.Context, args ...string) ([]byte, error) {
+	return c.run(ctx, args, c.runner(true))
+}
+
+// run invokes llt.exe, retrying a few times while LLT reports that it's busy
+// with another operation (e.g. two power-mode commands overlapping) or
+// llt.exe is being replaced by an update. Other failures that may be
+// transient are retried with backoff (see Client.Retries) until ctx ends.
+func (c *Client) run(ctx context.Context, args []string, runner CommandRunner) ([]byte, error) {
+	retries := 0
+	for attempt := 0; ; attempt++ {
+		if limiter := c.limiter(); limiter != nil {
+			if err := limiter.wait(ctx); err != nil {
+				return nil, c.checkTimeout(ctx, err)
+			}
+		}
+		output, err := c.timed(args, func() ([]byte, error) {
+			return runner.Run(ctx, args...)
+		})
+		if isUpdating(err) {
+			if attempt >= updateRetries {
+				return output, fmt.Errorf("%w (%s: %v)", ErrLLTBusyUpdating, c.lltPath, err)
+			}
+			select {
+			case <-ctx.Done():
+				return output, fmt.Errorf("%w (%s: %v)", ErrLLTBusyUpdating, c.lltPath, err)
+			case <-time.After(updateDelay):
+			}
+			continue
+		}
+		if isNotExecutable(err) {
+			return output, notExecutable(c.lltPath, err)
+		}
+		if needsElevation(err) {
+			return output, fmt.Errorf("%w: %s", ErrElevationRequired, c.lltPath)
+		}
+		err = c.checkTimeout(ctx, err)
+		if !isBusy(output, err) {
+			if c.isTransient(ctx, output, err) && retries < c.retries() {
+				select {
+				case <-ctx.Done():
+				case <-time.After(c.retryDelay(retries)):
+					retries++
+					continue
+				}
+			}
+			err = c.classifyExit(err)
+			if err != nil && retries > 0 {
+				err = fmt.Errorf("%w (gave up after %d attempts)", err, retries+1)
+			}
+			return output, err
+		}
+		if attempt == busyRetries {
+			return output, fmt.Errorf("%w, try again", ErrBusy)
+		}
+
+		select {
+		case <-ctx.Done():
+			return output, c.checkTimeout(ctx, ctx.Err())
+		case <-time.After(busyDelay):
+		}
+	}
+}
+
+// checkTimeout replaces errors caused by hitting the timeout (which surface
+// as a killed process or a bare "context deadline exceeded") with a message
+// that says what happened and how to fix it
+func (c *Client) checkTimeout(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("LLT did not respond within %s (try increasing --timeout): %w", c.timeout(), context.DeadlineExceeded)
+	}
+	return err
+}
+
+// timed measures an llt.exe invocation and reports it to Trace
+func (c *Client) timed(args []string, run func() ([]byte, error)) ([]byte, error) {
+	start := time.Now()
+	output, err := run()
+
+	call := Call{Args: args, Duration: time.Since(start), Err: err, Output: strings.TrimSpace(decodeOutput(output))}
+	c.calls = append(c.calls, call)
+	if c.Trace != nil {
+		c.Trace(call)
+	}
+
+	return output, err
+}
+
+// Calls returns every llt.exe invocation made by this client so far
+func (c *Client) Calls() []Call {
+	return c.calls
+}
+
+// Path returns the resolved path to llt.exe
+func (c *Client) Path() string {
+	return c.lltPath
+}
+
 // IsRunning checks if LLT is accessible
 func (c *Client) IsRunning() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	return c.Probe() == nil
+}
+
+// Probe makes the lightweight llt.exe call behind IsRunning and returns why
+// it failed, e.g. ErrLLTNotExecutable when Windows refused to start llt.exe
+func (c *Client) Probe() error {
+	ctx, cancel := c.context()
 	defer cancel()
-	cmd := exec.CommandContext(ctx, c.lltPath, "f", "get", "power-mode")
-	
-	// Hide console window
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow:    true,
-		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
-	}
 
-	err := cmd.Run()
-	return err == nil
+	output, err := c.output(ctx, "f", "get", "power-mode")
+
+	// The probe is the same call a CLI read makes, so its answer saves one
+	if err == nil && c.CacheMode && c.ReadSource != ReadSourceWMI && len(c.ExtraArgs) == 0 {
+		if mode, parseErr := c.parseMode(output); parseErr == nil {
+			c.cachedMode = mode
+		}
+	}
+	return err
 }
 
-// GetCurrentMode retrieves the current power mode
+// GetCurrentMode retrieves the current power mode from the configured
+// ReadSource, or from the cache while CacheMode is on
 func (c *Client) GetCurrentMode() (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	cmd := exec.CommandContext(ctx, c.lltPath, "f", "get", "power-mode")
-	
-	// Hide console window
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow:    true,
-		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	if c.CacheMode && c.cachedMode != "" {
+		return c.cachedMode, nil
+	}
+
+	mode, err := c.readMode()
+	if err == nil && c.CacheMode {
+		c.cachedMode = mode
+	}
+	return mode, err
+}
+
+// forgetMode drops the cached mode after a change that may have switched it
+func (c *Client) forgetMode() {
+	c.cachedMode = ""
+}
+
+// readMode reads the current power mode from the configured ReadSource
+func (c *Client) readMode() (string, error) {
+	switch c.ReadSource {
+	case ReadSourceWMI:
+		return c.getModeWMI()
+	case ReadSourceAuto:
+		mode, err := c.getModeCLI()
+		if err == nil {
+			return mode, nil
+		}
+		if wmiMode, wmiErr := c.getModeWMI(); wmiErr == nil {
+			return wmiMode, nil
+		}
+		return "", err
 	}
+	return c.getModeCLI()
+}
+
+// getModeCLI reads the current power mode via llt.exe
+func (c *Client) getModeCLI() (string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
 
-	output, err := cmd.Output()
+	output, err := c.output(ctx, c.withExtraArgs("f", "get", "power-mode")...)
 	if err != nil {
 		return "", fmt.Errorf("failed to get current mode: %w", err)
 	}
 
-	mode := strings.TrimSpace(string(output))
-	return mode, nil
+	return c.parseMode(output)
+}
+
+// parseMode turns the output of `f get power-mode` into a mode id
+func (c *Client) parseMode(output []byte) (string, error) {
+	text := decodeOutput(output)
+	value := c.modeValue(text)
+	if value == "" {
+		return "", fmt.Errorf("no power mode in LLT's output %q", strings.TrimSpace(text))
+	}
+	return c.mapMode(value), nil
+}
+
+// modeLabels are the labels some LLT builds print before the mode, either
+// on its own line above it or as "Power mode: Balance"
+var modeLabels = []string{"power mode", "power-mode", "powermode", "current power mode", "value"}
+
+// modeValue picks the mode out of `f get power-mode` output. Most builds
+// print just the value, but some add a header line above it or a note
+// below it. In order of preference it takes the value of a line with a known
+// label ("Power mode: Balance"), the last line naming a known mode, or the
+// last single-word line (a mode the helper doesn't know yet). Taking the
+// whole output would leave a multi-line string that never matches a mode
+// (and toggle would always fall back to the start of the cycle). Empty means
+// nothing looked like a mode.
+func (c *Client) modeValue(output string) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, line := range lines {
+		if label, value, ok := strings.Cut(line, ":"); ok && slices.ContainsFunc(modeLabels, func(known string) bool {
+			return strings.EqualFold(strings.TrimSpace(label), known)
+		}) {
+			return strings.TrimSpace(value)
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if c.isModeName(lines[i]) {
+			return lines[i]
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if !strings.ContainsAny(lines[i], " \t") {
+			return lines[i]
+		}
+	}
+	return ""
 }
 
 // SetMode sets the power mode to the specified value
 func (c *Client) SetMode(mode string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	cmd := exec.CommandContext(ctx, c.lltPath, "f", "set", "power-mode", mode)
-	
-	// Hide console window
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow:    true,
-		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	mode, err := sanitizeMode(mode)
+	if err != nil {
+		return err
 	}
 
-	err := cmd.Run()
+	err = c.runSet("power-mode", mode)
 	if err != nil {
 		return fmt.Errorf("failed to set mode to %s: %w", mode, err)
 	}
@@ -88,31 +524,137 @@ func (c *Client) SetMode(mode string) error {
 	return nil
 }
 
-// ListAvailableModes lists all available power modes
-func (c *Client) ListAvailableModes() ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// GetFeature retrieves the current value of an LLT feature (e.g. "battery")
+func (c *Client) GetFeature(name string) (string, error) {
+	if err := validateFeatureName(name); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := c.context()
 	defer cancel()
-	cmd := exec.CommandContext(ctx, c.lltPath, "f", "set", "power-mode", "-l")
-	
-	// Hide console window
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		HideWindow:    true,
-		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+
+	output, err := c.output(ctx, c.withExtraArgs("f", "get", name)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get feature %s: %w", name, err)
+	}
+
+	return strings.TrimSpace(decodeOutput(output)), nil
+}
+
+// SetFeature sets an LLT feature to the specified value
+func (c *Client) SetFeature(name, value string) error {
+	if err := validateFeatureName(name); err != nil {
+		return err
+	}
+	if err := validateFeatureValue(value); err != nil {
+		return err
 	}
 
-	output, err := cmd.Output()
+	err := c.runSet(name, value)
+	if err != nil {
+		return fmt.Errorf("failed to set feature %s to %s: %w", name, value, err)
+	}
+
+	return nil
+}
+
+// runSet runs `f set` for a feature. Some LLT releases only accept
+// `NAME=VALUE`, others only `NAME VALUE`; when LLT reports an unknown
+// argument the alternate form is tried and remembered for later calls.
+func (c *Client) runSet(name, value string) error {
+	// Features such as a custom mode's values can move the power mode too
+	c.forgetMode()
+
+	output, err := c.runSetSyntax(name, value, c.equalsSyntax)
+	if err == nil || !isUnknownArgument(output) {
+		return err
+	}
+
+	if _, altErr := c.runSetSyntax(name, value, !c.equalsSyntax); altErr != nil {
+		return err
+	}
+	c.equalsSyntax = !c.equalsSyntax
+	return nil
+}
+
+// runSetSyntax runs a single `f set` invocation in the requested syntax
+func (c *Client) runSetSyntax(name, value string, equals bool) ([]byte, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	args := []string{"f", "set", name, value}
+	if equals {
+		args = []string{"f", "set", name + "=" + value}
+	}
+
+	return c.combinedOutput(ctx, c.withExtraArgs(args...)...)
+}
+
+// withExtraArgs appends ExtraArgs to an llt.exe argument list
+func (c *Client) withExtraArgs(args ...string) []string {
+	return append(args, c.ExtraArgs...)
+}
+
+// isUnknownArgument reports whether LLT output complains about its arguments
+func isUnknownArgument(output []byte) bool {
+	text := strings.ToLower(decodeOutput(output))
+	return strings.Contains(text, "unknown argument") ||
+		strings.Contains(text, "unrecognized command or argument")
+}
+
+// ListAvailableModes lists all available power modes
+func (c *Client) ListAvailableModes() ([]string, error) {
+	values, err := c.listFeatureValues("power-mode")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list modes: %w", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 	var modes []string
+	for _, value := range values {
+		// A mapped line is taken whole, decoration and all
+		if id, ok := c.ModeMap[strings.ToLower(strings.TrimSpace(value))]; ok {
+			modes = append(modes, id)
+			continue
+		}
+		if token := modeToken(value); token != "" {
+			modes = append(modes, c.mapMode(token))
+		}
+	}
+
+	return modes, nil
+}
+
+// modeToken extracts the mode name from one line of `f set power-mode -l`
+// output, which some LLT builds decorate with bullets, indices or a
+// description (e.g. "1) Performance - high power" or "* quiet"): leading
+// characters that aren't letters are dropped, the name ends at the first
+// character that isn't a letter or digit, and the result is lowercased
+func modeToken(line string) string {
+	line = strings.TrimLeftFunc(line, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end := strings.IndexFunc(line, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }); end >= 0 {
+		line = line[:end]
+	}
+	return strings.ToLower(line)
+}
+
+// listFeatureValues returns the values LLT accepts for a feature on this device
+func (c *Client) listFeatureValues(name string) ([]string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	output, err := c.output(ctx, "f", "set", name, "-l")
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(decodeOutput(output)), "\n")
+	var values []string
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line != "" {
-			modes = append(modes, line)
+			values = append(values, line)
 		}
 	}
 
-	return modes, nil
+	return values, nil
 }
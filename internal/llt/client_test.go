@@ -0,0 +1,281 @@
+package llt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// exitCodeEnv makes TestHelperProcess exit with the given code
+const exitCodeEnv = "LLT_TEST_EXIT_CODE"
+
+// TestHelperProcess isn't a real test: exitError runs the test binary with
+// exitCodeEnv set, and it exits with that code
+func TestHelperProcess(t *testing.T) {
+	code := os.Getenv(exitCodeEnv)
+	if code == "" {
+		return
+	}
+	n, _ := strconv.Atoi(code)
+	os.Exit(n)
+}
+
+// exitError returns the error of a process that exited with code, as
+// llt.exe failing would produce
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), exitCodeEnv+"="+strconv.Itoa(code))
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != code {
+		t.Fatalf("helper process: got %v, want exit code %d", err, code)
+	}
+	return err
+}
+
+// fakeResponse is what the fake llt.exe prints and returns for one call
+type fakeResponse struct {
+	output string
+	err    error
+}
+
+// newFakeClient returns a client whose llt.exe answers from responses,
+// keyed by the space-joined arguments, and the calls it received. Unknown
+// calls fail the test. Retries are off so failures return at once.
+func newFakeClient(t *testing.T, responses map[string]fakeResponse) (*Client, *[][]string) {
+	t.Helper()
+	var calls [][]string
+	runner := CommandRunnerFunc(func(ctx context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		response, ok := responses[strings.Join(args, " ")]
+		if !ok {
+			t.Errorf("unexpected llt.exe call: %q", args)
+			return nil, errors.New("unexpected call")
+		}
+		return []byte(response.output), response.err
+	})
+
+	client := NewClientWithRunner(`C:\LLT\llt.exe`, runner)
+	client.Retries = -1
+	return client, &calls
+}
+
+func TestGetCurrentMode(t *testing.T) {
+	client, calls := newFakeClient(t, map[string]fakeResponse{
+		"f get power-mode": {output: "balance\r\n"},
+	})
+
+	mode, err := client.GetCurrentMode()
+	if err != nil {
+		t.Fatalf("GetCurrentMode: %v", err)
+	}
+	if mode != "balance" {
+		t.Errorf("GetCurrentMode = %q, want balance", mode)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("got %d llt.exe calls, want 1", len(*calls))
+	}
+}
+
+func TestGetCurrentModeError(t *testing.T) {
+	client, _ := newFakeClient(t, map[string]fakeResponse{
+		"f get power-mode": {err: exitError(t, 1)},
+	})
+
+	_, err := client.GetCurrentMode()
+	if err == nil || !strings.Contains(err.Error(), "failed to get current mode") {
+		t.Fatalf("GetCurrentMode error = %v, want it wrapped", err)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Errorf("GetCurrentMode error %v doesn't keep the exit error", err)
+	}
+}
+
+func TestSetMode(t *testing.T) {
+	client, calls := newFakeClient(t, map[string]fakeResponse{
+		"f set power-mode quiet": {},
+	})
+
+	if err := client.SetMode("  Quiet "); err != nil {
+		t.Fatalf("SetMode: %v", err)
+	}
+	want := [][]string{{"f", "set", "power-mode", "quiet"}}
+	if !slices.EqualFunc(*calls, want, slices.Equal) {
+		t.Errorf("calls = %q, want %q", *calls, want)
+	}
+}
+
+func TestSetModeEqualsSyntax(t *testing.T) {
+	client, calls := newFakeClient(t, map[string]fakeResponse{
+		"f set power-mode quiet":       {output: "Unknown argument: quiet", err: exitError(t, 1)},
+		"f set power-mode=quiet":       {},
+		"f set power-mode=performance": {},
+	})
+
+	if err := client.SetMode("quiet"); err != nil {
+		t.Fatalf("SetMode(quiet): %v", err)
+	}
+	// The accepted syntax is remembered, so the next call goes straight to it
+	if err := client.SetMode("performance"); err != nil {
+		t.Fatalf("SetMode(performance): %v", err)
+	}
+	want := [][]string{
+		{"f", "set", "power-mode", "quiet"},
+		{"f", "set", "power-mode=quiet"},
+		{"f", "set", "power-mode=performance"},
+	}
+	if !slices.EqualFunc(*calls, want, slices.Equal) {
+		t.Errorf("calls = %q, want %q", *calls, want)
+	}
+}
+
+func TestSetModeError(t *testing.T) {
+	client, _ := newFakeClient(t, map[string]fakeResponse{
+		"f set power-mode performance": {output: "Failed", err: exitError(t, 1)},
+	})
+
+	err := client.SetMode("performance")
+	if err == nil || !strings.Contains(err.Error(), "failed to set mode to performance") {
+		t.Errorf("SetMode error = %v, want it wrapped", err)
+	}
+}
+
+func TestListAvailableModes(t *testing.T) {
+	client, _ := newFakeClient(t, map[string]fakeResponse{
+		"f set power-mode -l": {output: "quiet\r\nbalance\r\n\r\nperformance\r\n"},
+	})
+
+	got, err := client.ListAvailableModes()
+	if err != nil {
+		t.Fatalf("ListAvailableModes: %v", err)
+	}
+	if want := []string{"quiet", "balance", "performance"}; !slices.Equal(got, want) {
+		t.Errorf("ListAvailableModes = %q, want %q", got, want)
+	}
+}
+
+func TestListAvailableModesError(t *testing.T) {
+	client, _ := newFakeClient(t, map[string]fakeResponse{
+		"f set power-mode -l": {err: exitError(t, 1)},
+	})
+
+	if _, err := client.ListAvailableModes(); err == nil || !strings.Contains(err.Error(), "failed to list modes") {
+		t.Errorf("ListAvailableModes error = %v, want it wrapped", err)
+	}
+}
+
+// currentModeFrom runs GetCurrentMode against a fake llt.exe printing output
+func currentModeFrom(t *testing.T, output string) (string, error) {
+	t.Helper()
+	client, _ := newFakeClient(t, map[string]fakeResponse{
+		"f get power-mode": {output: output},
+	})
+	return client.GetCurrentMode()
+}
+
+func TestGetCurrentModeOutputShapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"bare id", "performance\n", "performance"},
+		{"display name", "Balance\r\n", "balance"},
+		{"upper case", "QUIET\n", "quiet"},
+		{"mixed case", "PerFormance\n", "performance"},
+		{"label", "Power mode: Balance\n", "balance"},
+		{"label mixed case", "POWER-MODE: Quiet\n", "quiet"},
+		{"display name balanced", "Balanced\n", "balance"},
+		{"trailing note", "quiet\nNote: changes apply after a moment\n", "quiet"},
+		{"note after a labelled value", "Power mode: Performance\nRestart LLT to apply other settings\n", "performance"},
+		{"unknown mode passes through", "Extreme\n", "extreme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := currentModeFrom(t, tt.output)
+			if err != nil {
+				t.Fatalf("GetCurrentMode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetCurrentMode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCurrentModeUnparseable(t *testing.T) {
+	for _, output := range []string{"", "\r\n  \n", "Something went wrong here\n"} {
+		_, err := currentModeFrom(t, output)
+		if err == nil {
+			t.Errorf("GetCurrentMode(%q) succeeded, want an error", output)
+			continue
+		}
+		if !strings.Contains(err.Error(), strings.TrimSpace(output)) {
+			t.Errorf("GetCurrentMode(%q) error %q doesn't include the output", output, err)
+		}
+	}
+}
+
+func TestGetCurrentModeMultiLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"header line", "power-mode\nbalance\n", "balance"},
+		{"header and blank lines", "\r\nPower Mode\r\n\r\nperformance\r\n\r\n", "performance"},
+		{"label on its own line", "Current power mode\nquiet\n", "quiet"},
+		{"value after label", "LLT CLI\nPower mode: performance\n", "performance"},
+		{"indented value", "power-mode\n   quiet   \n", "quiet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := currentModeFrom(t, tt.output)
+			if err != nil {
+				t.Fatalf("GetCurrentMode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetCurrentMode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListAvailableModesDecorated(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"numbered", "1) Quiet\n2) Balance\n3) Performance\n", []string{"quiet", "balance", "performance"}},
+		{"numbered with descriptions", "1) Performance - high power\n2. Quiet - low noise\n", []string{"performance", "quiet"}},
+		{"bullets", "* quiet\n- balance\n• performance\n", []string{"quiet", "balance", "performance"}},
+		{"current marker", "> balance\n  quiet\n  performance\n", []string{"balance", "quiet", "performance"}},
+		{"current suffix", "quiet\nbalance (current)\nperformance\n", []string{"quiet", "balance", "performance"}},
+		{"brackets", "[quiet]\n[balance] *\n", []string{"quiet", "balance"}},
+		{"tabs and CRLF", "\tQuiet\r\n\tGodMode\r\n", []string{"quiet", "godmode"}},
+		{"localized", "Leise\nAusgeglichen\nLeistung\n", []string{"quiet", "balance", "performance"}},
+		{"no name on a line", "quiet\n---\nbalance\n", []string{"quiet", "balance"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newFakeClient(t, map[string]fakeResponse{
+				"f set power-mode -l": {output: tt.output},
+			})
+			got, err := client.ListAvailableModes()
+			if err != nil {
+				t.Fatalf("ListAvailableModes: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListAvailableModes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
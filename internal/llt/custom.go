@@ -0,0 +1,57 @@
+package llt
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CustomMode is the power mode LLT's custom tuning values belong to
+// (shown as Custom or GodMode depending on the LLT version)
+const CustomMode = "godmode"
+
+// SetCustomMode applies custom tuning values, which LLT only honours while
+// the power mode is already Custom. The mode is switched (and re-read to
+// verify) first if needed; if that fails no values are applied. Values are
+// applied in name order, stopping at the first failure.
+func (c *Client) SetCustomMode(values map[string]string) error {
+	if err := c.ensureCustomMode(); err != nil {
+		return fmt.Errorf("custom values not applied: %w", err)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if err := c.SetFeature(name, values[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureCustomMode switches to CustomMode unless it's already active
+func (c *Client) ensureCustomMode() error {
+	current, err := c.GetCurrentMode()
+	if err != nil {
+		return err
+	}
+	if current == CustomMode {
+		return nil
+	}
+
+	if err := c.SetMode(CustomMode); err != nil {
+		return err
+	}
+
+	current, err = c.GetCurrentMode()
+	if err != nil {
+		return fmt.Errorf("could not verify switch to %s: %w", CustomMode, err)
+	}
+	if current != CustomMode {
+		return fmt.Errorf("LLT accepted mode '%s' but the power mode is still '%s'", CustomMode, current)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package llt
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	kernel32               = windows.NewLazySystemDLL("kernel32.dll")
+	procGetConsoleOutputCP = kernel32.NewProc("GetConsoleOutputCP")
+	procGetOEMCP           = kernel32.NewProc("GetOEMCP")
+)
+
+// decodeOutput converts llt.exe output to a Go string. Output that is
+// already valid UTF-8 is used as-is; anything else was written in the
+// console's code page (the OEM code page, e.g. CP-850, on most non-English
+// systems) and is converted from that, so accented names survive.
+func decodeOutput(output []byte) string {
+	if len(output) == 0 || utf8.Valid(output) {
+		return string(output)
+	}
+
+	codePage, _, _ := procGetConsoleOutputCP.Call()
+	if codePage == 0 {
+		// No console attached (e.g. launched from a Stream Deck)
+		codePage, _, _ = procGetOEMCP.Call()
+	}
+
+	return decodeWithCodePage(output, uint32(codePage))
+}
+
+// decodeWithCodePage converts output written in codePage to a Go string,
+// returning it unchanged if the conversion fails
+func decodeWithCodePage(output []byte, codePage uint32) string {
+	if len(output) == 0 {
+		return ""
+	}
+
+	n, err := windows.MultiByteToWideChar(codePage, 0, &output[0], int32(len(output)), nil, 0)
+	if err != nil || n == 0 {
+		return string(output)
+	}
+	wide := make([]uint16, n)
+	if _, err := windows.MultiByteToWideChar(codePage, 0, &output[0], int32(len(output)), &wide[0], n); err != nil {
+		return string(output)
+	}
+
+	return string(utf16.Decode(wide))
+}
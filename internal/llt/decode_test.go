@@ -0,0 +1,36 @@
+//go:build windows
+
+package llt
+
+import "testing"
+
+func TestDecodeWithCodePage(t *testing.T) {
+	tests := []struct {
+		name     string
+		codePage uint32
+		input    []byte
+		want     string
+	}{
+		{"CP-850", 850, []byte("\x90quilibr\x82\r\n"), "Équilibré\r\n"},
+		{"CP-850 umlaut", 850, []byte("Leistungsf\x84hig"), "Leistungsfähig"},
+		{"CP-1252", 1252, []byte("\xc9quilibr\xe9"), "Équilibré"},
+		{"CP-1251", 1251, []byte("\xf2\xe8\xf5\xe8\xe9"), "тихий"},
+		{"ASCII", 850, []byte("balance"), "balance"},
+		{"empty", 850, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeWithCodePage(tt.input, tt.codePage); got != tt.want {
+				t.Errorf("decodeWithCodePage(%q, %d) = %q, want %q", tt.input, tt.codePage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeOutputKeepsUTF8(t *testing.T) {
+	for _, s := range []string{"", "quiet\r\n", "équilibré", "安静"} {
+		if got := decodeOutput([]byte(s)); got != s {
+			t.Errorf("decodeOutput(%q) = %q, want it unchanged", s, got)
+		}
+	}
+}
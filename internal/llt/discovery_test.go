@@ -0,0 +1,83 @@
+//go:build windows
+
+package llt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeInstall creates an empty llt.exe where the LLT installer puts it
+// under base and returns its path
+func fakeInstall(t *testing.T, base string) string {
+	t.Helper()
+	path := filepath.Join(base, "Programs", "LenovoLegionToolkit", "llt.exe")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewClientFromBase(t *testing.T) {
+	base := t.TempDir()
+	want := fakeInstall(t, base)
+
+	client, err := NewClientFromBase(base)
+	if err != nil {
+		t.Fatalf("NewClientFromBase: %v", err)
+	}
+	if client.Path() != want {
+		t.Errorf("Path = %q, want %q", client.Path(), want)
+	}
+}
+
+func TestNewClientFromBaseMissing(t *testing.T) {
+	base := t.TempDir()
+	// LLT's directory without llt.exe in it, as an interrupted install leaves it
+	if err := os.MkdirAll(filepath.Join(base, "Programs", "LenovoLegionToolkit"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{base, filepath.Join(base, "missing")} {
+		if _, err := NewClientFromBase(dir); !errors.Is(err, ErrLLTNotFound) {
+			t.Errorf("NewClientFromBase(%s) error = %v, want ErrLLTNotFound", dir, err)
+		}
+	}
+}
+
+func TestNewClientAt(t *testing.T) {
+	path := fakeInstall(t, t.TempDir())
+
+	client, err := NewClientAt(path)
+	if err != nil {
+		t.Fatalf("NewClientAt: %v", err)
+	}
+	if client.Path() != path {
+		t.Errorf("Path = %q, want %q", client.Path(), path)
+	}
+
+	if _, err := NewClientAt(filepath.Dir(path)); err == nil {
+		t.Error("NewClientAt of a directory succeeded, want an error")
+	}
+	if _, err := NewClientAt(path + ".missing"); !errors.Is(err, ErrLLTNotFound) {
+		t.Errorf("NewClientAt of a missing file: error = %v, want ErrLLTNotFound", err)
+	}
+}
+
+func TestNewClientPathEnv(t *testing.T) {
+	path := fakeInstall(t, t.TempDir())
+	t.Setenv(PathEnv, path)
+
+	client, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if client.Path() != path {
+		t.Errorf("Path = %q, want %q from %s", client.Path(), path, PathEnv)
+	}
+}
@@ -0,0 +1,207 @@
+package llt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// ErrFeatureUnsupported is returned when the installed LLT doesn't support
+// the requested feature or command
+var ErrFeatureUnsupported = errors.New("not supported by the installed LLT version")
+
+// ErrBusy is returned when LLT kept reporting that another operation was in
+// progress after a few retries
+var ErrBusy = errors.New("LLT busy")
+
+// ErrNoBattery is returned when Windows reports no battery, or no charge
+// level for it (e.g. on a desktop)
+var ErrNoBattery = errors.New("no battery")
+
+// ErrPowerSourceUnknown is returned when Windows can't tell whether the
+// laptop is on AC
+var ErrPowerSourceUnknown = errors.New("power source unknown")
+
+// ErrUnsupportedPlatform is returned by every client on platforms other
+// than Windows, where the package only builds
+var ErrUnsupportedPlatform = errors.New("LLT requires Windows")
+
+// ErrRestartRequired is returned when LLT reports that a change only takes
+// effect once LLT is restarted
+var ErrRestartRequired = errors.New("LLT must be restarted for the change to take effect")
+
+// DefaultExitCodes maps llt.exe exit codes to the errors they stand for.
+// LLT doesn't document its exit codes, so this only holds the ones believed
+// to carry a meaning beyond 1 (a generic failure, left unmapped);
+// Client.ExitCodes replaces the table as LLT's actual codes are learned.
+var DefaultExitCodes = map[int]error{
+	2: ErrFeatureUnsupported,
+	3: ErrRestartRequired,
+}
+
+// classifyExit wraps an llt.exe exit error with the error its exit code
+// maps to. The exit error stays in the chain, so its stderr is still
+// reachable with errors.As.
+func (c *Client) classifyExit(err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	codes := c.ExitCodes
+	if codes == nil {
+		codes = DefaultExitCodes
+	}
+	mapped, ok := codes[exitErr.ExitCode()]
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%w (llt.exe exit code %d): %w", mapped, exitErr.ExitCode(), err)
+}
+
+// errorText returns the output LLT printed for a failed invocation,
+// including stderr captured on the exit error
+func errorText(output []byte, err error) string {
+	text := decodeOutput(output)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		text += decodeOutput(exitErr.Stderr)
+	}
+	return strings.ToLower(text)
+}
+
+// isUnsupported reports whether a failed invocation means LLT doesn't know
+// the command or feature at all
+func isUnsupported(output []byte, err error) bool {
+	if err == nil {
+		return false
+	}
+	text := errorText(output, err)
+	return strings.Contains(text, "unrecognized command or argument") ||
+		strings.Contains(text, "unknown command") ||
+		strings.Contains(text, "not supported")
+}
+
+// isTransient reports whether a failed invocation may succeed if simply run
+// again: llt.exe exited with an error that neither maps to a known exit code
+// nor says the command itself was wrong
+func (c *Client) isTransient(ctx context.Context, output []byte, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if isUnsupported(output, err) || isUnknownArgument(output) {
+		return false
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	codes := c.ExitCodes
+	if codes == nil {
+		codes = DefaultExitCodes
+	}
+	_, mapped := codes[exitErr.ExitCode()]
+	return !mapped
+}
+
+// isBusy reports whether a failed invocation means LLT is in the middle of
+// another operation and the same call may succeed shortly
+func isBusy(output []byte, err error) bool {
+	if err == nil {
+		return false
+	}
+	text := errorText(output, err)
+	return strings.Contains(text, "busy") ||
+		strings.Contains(text, "in progress")
+}
+
+// ErrLLTNotFound is returned when no llt.exe exists where the helper looked,
+// i.e. Legion Toolkit isn't installed (or LLT_PATH points elsewhere)
+var ErrLLTNotFound = errors.New("LLT not found")
+
+// ErrLLTNotExecutable is returned when llt.exe exists but Windows won't let
+// the helper open or start it, typically because antivirus, SmartScreen or
+// file permissions block it
+var ErrLLTNotExecutable = errors.New("llt.exe exists but can't be run (check antivirus/SmartScreen and the file's permissions)")
+
+// ErrLLTBusyUpdating is returned when llt.exe is locked or briefly missing
+// because Legion Toolkit is replacing it during a self-update
+var ErrLLTBusyUpdating = errors.New("Legion Toolkit is updating, try again shortly")
+
+// ErrElevationRequired is returned when an operation needs the helper to run
+// as administrator
+var ErrElevationRequired = errors.New("administrator rights required (run from an elevated prompt, or pass --elevate)")
+
+// Windows errors that mean the llt.exe image itself is blocked, rather than
+// LLT failing once started
+const (
+	errorFileNotFound      = syscall.Errno(2)
+	errorAccessDenied      = syscall.Errno(5)
+	errorSharingViolation  = syscall.Errno(32)
+	errorLockViolation     = syscall.Errno(33)
+	errorBadExeFormat      = syscall.Errno(193)
+	errorVirusInfected     = syscall.Errno(225)
+	errorVirusDeleted      = syscall.Errno(226)
+	errorElevationRequired = syscall.Errno(740)
+)
+
+// isNotExecutable reports whether err means llt.exe couldn't be opened or
+// started at all. An exit error means it ran, so that never counts.
+func isNotExecutable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false
+	}
+	for _, blocked := range []syscall.Errno{errorAccessDenied, errorBadExeFormat, errorVirusInfected, errorVirusDeleted} {
+		if errors.Is(err, blocked) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUpdating reports whether llt.exe couldn't be started because it's being
+// replaced: the installer holds it open or has removed it for a moment.
+// The path was found when the client was created, so a missing file means
+// it went away since, not that LLT isn't installed.
+func isUpdating(err error) bool {
+	var exitErr *exec.ExitError
+	if err == nil || errors.As(err, &exitErr) {
+		return false
+	}
+	return errors.Is(err, errorSharingViolation) || errors.Is(err, errorLockViolation) || errors.Is(err, errorFileNotFound)
+}
+
+// needsElevation reports whether llt.exe refused to start because its
+// manifest asks for administrator rights the helper doesn't have
+func needsElevation(err error) bool {
+	var exitErr *exec.ExitError
+	return err != nil && !errors.As(err, &exitErr) && errors.Is(err, errorElevationRequired)
+}
+
+// notExecutable wraps a failure to open or start llt.exe at path
+func notExecutable(path string, err error) error {
+	return fmt.Errorf("%w: %s: %v", ErrLLTNotExecutable, path, err)
+}
+
+// probeExecutable opens llt.exe for reading, the cheapest check that
+// catches a file locked down by permissions or quarantined by antivirus
+// before the first llt.exe call fails with a bare exit error
+func probeExecutable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if isNotExecutable(err) {
+			return notExecutable(path, err)
+		}
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return f.Close()
+}
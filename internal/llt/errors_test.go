@@ -0,0 +1,196 @@
+package llt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startError is how a failure to start llt.exe surfaces from exec
+func startError(errno syscall.Errno) error {
+	return &os.PathError{Op: "fork/exec", Path: `C:\LLT\llt.exe`, Err: errno}
+}
+
+// sequenceClient returns a client whose llt.exe answers each call with the
+// next response, and a pointer to the number of calls made
+func sequenceClient(t *testing.T, responses ...fakeResponse) (*Client, *int) {
+	t.Helper()
+	calls := 0
+	runner := CommandRunnerFunc(func(ctx context.Context, args ...string) ([]byte, error) {
+		if calls >= len(responses) {
+			t.Fatalf("unexpected llt.exe call %d: %q", calls+1, args)
+		}
+		response := responses[calls]
+		calls++
+		return []byte(response.output), response.err
+	})
+	return NewClientWithRunner(`C:\LLT\llt.exe`, runner), &calls
+}
+
+func TestStartErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		errno syscall.Errno
+		want  error
+	}{
+		{"access denied", errorAccessDenied, ErrLLTNotExecutable},
+		{"virus infected", errorVirusInfected, ErrLLTNotExecutable},
+		{"bad exe format", errorBadExeFormat, ErrLLTNotExecutable},
+		{"elevation required", errorElevationRequired, ErrElevationRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := sequenceClient(t, fakeResponse{err: startError(tt.errno)})
+
+			_, err := client.GetCurrentMode()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if *calls != 1 {
+				t.Errorf("got %d llt.exe calls, want 1 (no retries)", *calls)
+			}
+		})
+	}
+}
+
+func TestNotFoundDuringCall(t *testing.T) {
+	// llt.exe was found when the client was created, so it disappearing
+	// means an update is replacing it; the timeout ends the wait early
+	client, _ := sequenceClient(t,
+		fakeResponse{err: startError(errorFileNotFound)},
+		fakeResponse{err: startError(errorFileNotFound)},
+		fakeResponse{err: startError(errorFileNotFound)},
+	)
+	client.Timeout = 50 * time.Millisecond
+
+	_, err := client.GetCurrentMode()
+	if !errors.Is(err, ErrLLTBusyUpdating) {
+		t.Errorf("error = %v, want ErrLLTBusyUpdating", err)
+	}
+}
+
+func TestBusyUpdatingRetried(t *testing.T) {
+	client, calls := sequenceClient(t,
+		fakeResponse{err: startError(errorSharingViolation)},
+		fakeResponse{output: "quiet\n"},
+	)
+
+	mode, err := client.GetCurrentMode()
+	if err != nil {
+		t.Fatalf("GetCurrentMode: %v", err)
+	}
+	if mode != "quiet" || *calls != 2 {
+		t.Errorf("got %q after %d calls, want quiet after 2", mode, *calls)
+	}
+}
+
+func TestBusyRetried(t *testing.T) {
+	client, calls := sequenceClient(t,
+		fakeResponse{output: "Another operation is in progress", err: exitError(t, 1)},
+		fakeResponse{output: "balance\n"},
+	)
+
+	mode, err := client.GetCurrentMode()
+	if err != nil {
+		t.Fatalf("GetCurrentMode: %v", err)
+	}
+	if mode != "balance" || *calls != 2 {
+		t.Errorf("got %q after %d calls, want balance after 2", mode, *calls)
+	}
+}
+
+func TestDefaultExitCodes(t *testing.T) {
+	tests := []struct {
+		code int
+		want error
+	}{
+		{2, ErrFeatureUnsupported},
+		{3, ErrRestartRequired},
+	}
+	for _, tt := range tests {
+		client, calls := sequenceClient(t, fakeResponse{err: exitError(t, tt.code)})
+
+		_, err := client.GetCurrentMode()
+		if !errors.Is(err, tt.want) {
+			t.Errorf("exit code %d: error = %v, want %v", tt.code, err, tt.want)
+		}
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Errorf("exit code %d: error %v doesn't keep the exit error", tt.code, err)
+		}
+		if *calls != 1 {
+			t.Errorf("exit code %d: got %d llt.exe calls, want 1 (mapped codes aren't retried)", tt.code, *calls)
+		}
+	}
+}
+
+func TestUnmappedExitCode(t *testing.T) {
+	client, _ := sequenceClient(t, fakeResponse{err: exitError(t, 1)})
+	client.Retries = -1
+
+	_, err := client.GetCurrentMode()
+	if err == nil {
+		t.Fatal("GetCurrentMode succeeded, want an error")
+	}
+	for _, typed := range []error{ErrFeatureUnsupported, ErrRestartRequired, ErrBusy} {
+		if errors.Is(err, typed) {
+			t.Errorf("exit code 1 mapped to %v", typed)
+		}
+	}
+}
+
+func TestExitCodesOverride(t *testing.T) {
+	client, _ := sequenceClient(t, fakeResponse{err: exitError(t, 2)})
+	client.ExitCodes = map[int]error{2: ErrRestartRequired}
+
+	if _, err := client.GetCurrentMode(); !errors.Is(err, ErrRestartRequired) || errors.Is(err, ErrFeatureUnsupported) {
+		t.Errorf("error = %v, want only ErrRestartRequired", err)
+	}
+}
+
+func TestTransientExitRetried(t *testing.T) {
+	client, calls := sequenceClient(t,
+		fakeResponse{err: exitError(t, 1)},
+		fakeResponse{output: "performance\n"},
+	)
+	client.RetryDelay = time.Millisecond
+
+	mode, err := client.GetCurrentMode()
+	if err != nil {
+		t.Fatalf("GetCurrentMode: %v", err)
+	}
+	if mode != "performance" || *calls != 2 {
+		t.Errorf("got %q after %d calls, want performance after 2", mode, *calls)
+	}
+}
+
+func TestTransientExitGivesUp(t *testing.T) {
+	failure := fakeResponse{err: exitError(t, 1)}
+	client, calls := sequenceClient(t, failure, failure, failure)
+	client.RetryDelay = time.Millisecond
+
+	_, err := client.GetCurrentMode()
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("error = %v, want it to count 3 attempts", err)
+	}
+	if *calls != DefaultRetries+1 {
+		t.Errorf("got %d llt.exe calls, want %d", *calls, DefaultRetries+1)
+	}
+}
+
+func TestUnsupportedNotRetried(t *testing.T) {
+	client, calls := sequenceClient(t, fakeResponse{output: "Feature not supported", err: exitError(t, 1)})
+	client.RetryDelay = time.Millisecond
+
+	if _, err := client.GetCurrentMode(); err == nil {
+		t.Fatal("GetCurrentMode succeeded, want an error")
+	}
+	if *calls != 1 {
+		t.Errorf("got %d llt.exe calls, want 1", *calls)
+	}
+}
@@ -0,0 +1,97 @@
+package llt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// KnownFeatures are the LLT features whose availability doctor reports
+var KnownFeatures = []string{
+	"power-mode",
+	"battery",
+	"white-keyboard-backlight",
+	"always-on-usb",
+	"fn-lock",
+	"hybrid-mode",
+	"overdrive",
+	"refresh-rate",
+	"touchpad-lock",
+	"win-key",
+}
+
+// Where a feature's availability was learned from
+const (
+	FeatureSourceSettings = "settings" // named in LLT's settings file
+	FeatureSourceProbe    = "probe"    // an `f get` call succeeded or failed
+)
+
+// FeatureStatus is whether one feature is available on this device
+type FeatureStatus struct {
+	Name      string
+	Available bool
+	Source    string
+}
+
+// DetectFeatures reports which of the named features are available. Features
+// that LLT's settings file has an entry for are taken as available without
+// spawning llt.exe; the rest (or all, if the file can't be found or parsed)
+// are probed with `f get`.
+func (c *Client) DetectFeatures(names []string) []FeatureStatus {
+	known, _ := settingsFeatures(c.settingsPaths())
+
+	statuses := make([]FeatureStatus, 0, len(names))
+	for _, name := range names {
+		if known[normalizeKey(name)] {
+			statuses = append(statuses, FeatureStatus{Name: name, Available: true, Source: FeatureSourceSettings})
+			continue
+		}
+		_, err := c.GetFeature(name)
+		statuses = append(statuses, FeatureStatus{Name: name, Available: err == nil, Source: FeatureSourceProbe})
+	}
+	return statuses
+}
+
+// settingsPaths lists where LLT's settings may be, most specific first: next
+// to llt.exe (portable installs), then the per-user location
+func (c *Client) settingsPaths() []string {
+	return []string{filepath.Join(filepath.Dir(c.lltPath), "settings.json"), SettingsPath()}
+}
+
+// settingsFeatures reads the first settings file that exists and returns its
+// top-level keys, normalized with normalizeKey
+func settingsFeatures(paths []string) (map[string]bool, error) {
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var settings map[string]json.RawMessage
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse LLT settings %s: %w", path, err)
+		}
+
+		keys := make(map[string]bool, len(settings))
+		for key := range settings {
+			keys[normalizeKey(key)] = true
+		}
+		return keys, nil
+	}
+	return nil, fmt.Errorf("LLT settings not found")
+}
+
+// normalizeKey lowercases a name and drops everything but letters and
+// digits, so a feature "white-keyboard-backlight" matches a settings key
+// "WhiteKeyboardBacklight"
+func normalizeKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
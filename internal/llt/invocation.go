@@ -0,0 +1,69 @@
+package llt
+
+import (
+	"context"
+	"os"
+)
+
+// Invocation describes an llt.exe call without running it
+type Invocation struct {
+	Path          string   `json:"path"`
+	Args          []string `json:"args"`
+	Dir           string   `json:"dir"`
+	Env           []string `json:"env"`
+	HideWindow    bool     `json:"hideWindow"`
+	CreationFlags uint32   `json:"creationFlags"`
+}
+
+// GetInvocation returns the call GetFeature would make for name
+func (c *Client) GetInvocation(name string) (Invocation, error) {
+	if err := validateFeatureName(name); err != nil {
+		return Invocation{}, err
+	}
+	return c.invocation(c.withExtraArgs("f", "get", name)...), nil
+}
+
+// SetInvocation returns the first call SetFeature (or SetMode, for
+// power-mode) would make. If LLT rejects that syntax the client retries
+// with the alternate `NAME=VALUE` or `NAME VALUE` form.
+func (c *Client) SetInvocation(name, value string) (Invocation, error) {
+	if err := validateFeatureName(name); err != nil {
+		return Invocation{}, err
+	}
+	var err error
+	if name == "power-mode" {
+		value, err = sanitizeMode(value)
+	} else {
+		err = validateFeatureValue(value)
+	}
+	if err != nil {
+		return Invocation{}, err
+	}
+
+	args := []string{"f", "set", name, value}
+	if c.equalsSyntax {
+		args = []string{"f", "set", name + "=" + value}
+	}
+	return c.invocation(c.withExtraArgs(args...)...), nil
+}
+
+// invocation builds the command the client would run for args
+func (c *Client) invocation(args ...string) Invocation {
+	cmd := c.command(context.Background(), args...)
+
+	// A nil Env means the child inherits this process's environment
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	hide, flags := windowFlags(cmd)
+	return Invocation{
+		Path:          cmd.Path,
+		Args:          cmd.Args,
+		Dir:           cmd.Dir,
+		Env:           env,
+		HideWindow:    hide,
+		CreationFlags: flags,
+	}
+}
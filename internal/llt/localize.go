@@ -0,0 +1,83 @@
+package llt
+
+import "strings"
+
+// localizedModes maps the power mode names localized LLT builds print back
+// to the English ids used everywhere else. Keys are lowercase.
+var localizedModes = map[string]string{
+	// German
+	"leise":             "quiet",
+	"ausgeglichen":      "balance",
+	"leistung":          "performance",
+	"benutzerdefiniert": "godmode",
+	// French
+	"silencieux":   "quiet",
+	"équilibré":    "balance",
+	"equilibre":    "balance",
+	"personnalisé": "godmode",
+	// Spanish / Portuguese
+	"silencioso":    "quiet",
+	"equilibrado":   "balance",
+	"rendimiento":   "performance",
+	"desempenho":    "performance",
+	"personalizado": "godmode",
+	// Italian
+	"silenzioso":     "quiet",
+	"bilanciato":     "balance",
+	"prestazioni":    "performance",
+	"personalizzato": "godmode",
+	// Polish
+	"cichy":          "quiet",
+	"zrównoważony":   "balance",
+	"wydajność":      "performance",
+	"niestandardowy": "godmode",
+	// Russian
+	"тихий":              "quiet",
+	"сбалансированный":   "balance",
+	"производительность": "performance",
+	"пользовательский":   "godmode",
+	// Chinese (Simplified)
+	"安静":  "quiet",
+	"均衡":  "balance",
+	"性能":  "performance",
+	"自定义": "godmode",
+	// English display name some builds print instead of the id
+	"balanced": "balance",
+}
+
+// mapMode translates an LLT mode name to its canonical id, trying the
+// user's ModeMap (keys lowercase) before the built-in translations
+func (c *Client) mapMode(name string) string {
+	if id, ok := c.ModeMap[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return id
+	}
+	return canonicalMode(name)
+}
+
+// canonicalMode translates a localized or display mode name ("Balance") to
+// its English id. Names that aren't recognized are returned lowercased.
+func canonicalMode(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if id, ok := localizedModes[name]; ok {
+		return id
+	}
+	return name
+}
+
+// isModeName reports whether name is a mode mapMode knows: a ModeMap key, a
+// localized name or one of LLT's own ids
+func (c *Client) isModeName(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := c.ModeMap[name]; ok {
+		return true
+	}
+	if _, ok := localizedModes[name]; ok {
+		return true
+	}
+	for _, id := range wmiPowerModes {
+		if id == name {
+			return true
+		}
+	}
+	return false
+}
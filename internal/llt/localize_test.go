@@ -0,0 +1,72 @@
+package llt
+
+import "testing"
+
+func TestCanonicalMode(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"german", "Leise", "quiet"},
+		{"german custom", "Benutzerdefiniert", "godmode"},
+		{"french", "Équilibré", "balance"},
+		{"french without accents", "equilibre", "balance"},
+		{"spanish", "Rendimiento", "performance"},
+		{"portuguese", "Desempenho", "performance"},
+		{"italian", "Silenzioso", "quiet"},
+		{"polish", "Wydajność", "performance"},
+		{"russian", "Сбалансированный", "balance"},
+		{"chinese", "安静", "quiet"},
+		{"english display name", "Balanced", "balance"},
+		{"english id passes through", "Performance", "performance"},
+		{"surrounding space", "  Leistung \r", "performance"},
+		{"missing key", "Turbo", "turbo"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalMode(tt.in); got != tt.want {
+				t.Errorf("canonicalMode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCurrentModeLocalized(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"Leistung\r\n", "performance"},
+		{"Silencieux\n", "quiet"},
+		{"Power mode: Bilanciato\n", "balance"},
+		{"Пользовательский\n", "godmode"},
+		{"性能\n", "performance"},
+		{"balance\n", "balance"},
+	}
+	for _, tt := range tests {
+		got, err := currentModeFrom(t, tt.output)
+		if err != nil {
+			t.Errorf("GetCurrentMode(%q): %v", tt.output, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("GetCurrentMode(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestIsModeName(t *testing.T) {
+	client := NewClientWithRunner(`C:\LLT\llt.exe`, nil)
+	for _, name := range []string{"Leise", "équilibré", "QUIET", "godmode", "Balanced"} {
+		if !client.isModeName(name) {
+			t.Errorf("isModeName(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"", "turbo", "Power mode"} {
+		if client.isModeName(name) {
+			t.Errorf("isModeName(%q) = true, want false", name)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package llt
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ModeForIndex translates LLT's numeric power mode index (1 quiet,
+// 2 balance, 3 performance, 255 godmode, as used by the Lenovo WMI
+// interface) to its mode name, checking that the device offers it
+func (c *Client) ModeForIndex(index int) (string, error) {
+	mode, ok := wmiPowerModes[strconv.Itoa(index)]
+	if !ok {
+		return "", fmt.Errorf("unknown LLT mode index %d (use 1, 2, 3 or 255)", index)
+	}
+
+	available, err := c.ListAvailableModes()
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(available, mode) {
+		return "", fmt.Errorf("mode index %d (%s) is not available on this device", index, mode)
+	}
+
+	return mode, nil
+}
+
+// IndexForMode returns LLT's numeric index for a mode name, or 0 if it has none
+func IndexForMode(mode string) int {
+	for value, name := range wmiPowerModes {
+		if name == mode {
+			index, _ := strconv.Atoi(value)
+			return index
+		}
+	}
+	return 0
+}
+
+// SetModeByIndex sets the power mode by LLT's numeric index
+func (c *Client) SetModeByIndex(index int) error {
+	mode, err := c.ModeForIndex(index)
+	if err != nil {
+		return err
+	}
+	return c.SetMode(mode)
+}
+
+// GetCurrentModeIndex returns LLT's numeric index for the current power mode
+func (c *Client) GetCurrentModeIndex() (int, error) {
+	mode, err := c.GetCurrentMode()
+	if err != nil {
+		return 0, err
+	}
+
+	index := IndexForMode(mode)
+	if index == 0 {
+		return 0, fmt.Errorf("power mode '%s' has no LLT index", mode)
+	}
+	return index, nil
+}
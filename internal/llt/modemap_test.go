@@ -0,0 +1,64 @@
+package llt
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestModeMapCurrentMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"mapped", "Turbo\n", "performance"},
+		{"mapped upper case", "TURBO\r\n", "performance"},
+		{"mapped after label", "Power mode: Whisper\n", "quiet"},
+		{"map wins over translation", "Leise\n", "balance"},
+		{"unmapped translated", "Leistung\n", "performance"},
+		{"unmapped passes through", "Extreme\n", "extreme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newFakeClient(t, map[string]fakeResponse{
+				"f get power-mode": {output: tt.output},
+			})
+			client.ModeMap = map[string]string{"turbo": "performance", "whisper": "quiet", "leise": "balance"}
+
+			got, err := client.GetCurrentMode()
+			if err != nil {
+				t.Fatalf("GetCurrentMode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetCurrentMode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModeMapListAvailableModes(t *testing.T) {
+	client, _ := newFakeClient(t, map[string]fakeResponse{
+		"f set power-mode -l": {output: "Whisper\nBALANCE\nTurbo Boost\nExtreme\n"},
+	})
+	// A whole decorated line can be mapped, not just the extracted name
+	client.ModeMap = map[string]string{"whisper": "quiet", "turbo boost": "performance"}
+
+	got, err := client.ListAvailableModes()
+	if err != nil {
+		t.Fatalf("ListAvailableModes: %v", err)
+	}
+	if want := []string{"quiet", "balance", "performance", "extreme"}; !slices.Equal(got, want) {
+		t.Errorf("ListAvailableModes = %q, want %q", got, want)
+	}
+}
+
+func TestModeMapIsModeName(t *testing.T) {
+	client := NewClientWithRunner(`C:\LLT\llt.exe`, nil)
+	if client.isModeName("Turbo") {
+		t.Error("isModeName(Turbo) = true before it was mapped")
+	}
+	client.ModeMap = map[string]string{"turbo": "performance"}
+	if !client.isModeName(" TURBO ") {
+		t.Error("isModeName(TURBO) = false, want true once mapped")
+	}
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package llt
+
+import "os/exec"
+
+// LLT is a Windows application; elsewhere the package only builds, for
+// tooling and CI, and every client fails with ErrUnsupportedPlatform
+
+func checkPlatform() error {
+	return ErrUnsupportedPlatform
+}
+
+func hideWindow(cmd *exec.Cmd) {}
+
+func windowFlags(cmd *exec.Cmd) (bool, uint32) {
+	return false, 0
+}
+
+func decodeOutput(output []byte) string {
+	return string(output)
+}
+
+// OnACPower always fails with ErrUnsupportedPlatform
+func OnACPower() (bool, error) {
+	return false, ErrUnsupportedPlatform
+}
+
+// GetBatteryPercent always fails with ErrUnsupportedPlatform
+func (c *Client) GetBatteryPercent() (int, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+// Version always fails with ErrUnsupportedPlatform
+func (c *Client) Version() (string, error) {
+	return "", ErrUnsupportedPlatform
+}
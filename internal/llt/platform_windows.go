@@ -0,0 +1,32 @@
+//go:build windows
+
+package llt
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNoWindow is CREATE_NO_WINDOW
+const createNoWindow = 0x08000000
+
+// checkPlatform reports whether LLT can be driven on this platform
+func checkPlatform() error {
+	return nil
+}
+
+// hideWindow keeps a child process from flashing a console window
+func hideWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow:    true,
+		CreationFlags: createNoWindow,
+	}
+}
+
+// windowFlags returns how hideWindow set up cmd, for Invocation
+func windowFlags(cmd *exec.Cmd) (bool, uint32) {
+	if cmd.SysProcAttr == nil {
+		return false, 0
+	}
+	return cmd.SysProcAttr.HideWindow, cmd.SysProcAttr.CreationFlags
+}
@@ -0,0 +1,53 @@
+package llt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LLT's automation is exposed on the CLI through Quick Actions (automation
+// pipelines that can be run on demand), so profiles map onto those:
+//
+//	llt.exe quickAction --list   lists the Quick Action names
+//	llt.exe quickAction NAME     runs one
+
+// ListProfiles lists the LLT automation profiles (Quick Actions) that can be activated
+func (c *Client) ListProfiles() ([]string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	output, err := c.output(ctx, "quickAction", "--list")
+	if isUnsupported(output, err) {
+		return nil, fmt.Errorf("automation profiles: %w", ErrFeatureUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var profiles []string
+	for _, line := range strings.Split(decodeOutput(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			profiles = append(profiles, line)
+		}
+	}
+
+	return profiles, nil
+}
+
+// ActivateProfile runs the named LLT automation profile (Quick Action)
+func (c *Client) ActivateProfile(name string) error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	// A Quick Action may switch the power mode
+	c.forgetMode()
+	output, err := c.combinedOutput(ctx, "quickAction", name)
+	if isUnsupported(output, err) {
+		return fmt.Errorf("automation profiles: %w", ErrFeatureUnsupported)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to activate profile %s: %w", name, err)
+	}
+
+	return nil
+}
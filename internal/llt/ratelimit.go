@@ -0,0 +1,63 @@
+package llt
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket capping how many llt.exe processes are
+// started per second. It holds up to one second's worth of tokens, so a short
+// burst goes through immediately and a longer one is spread out.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	burst := math.Max(1, math.Floor(rate))
+	return &rateLimiter{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes a token, sleeping until one is available or ctx is done. A
+// token reserved by a wait that ctx cuts short is handed back.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// limiter returns the client's spawn limiter, or nil when MaxRate is unset.
+// It's created on first use since MaxRate is set after NewClient.
+func (c *Client) limiter() *rateLimiter {
+	c.limiterOnce.Do(func() {
+		if c.MaxRate > 0 {
+			c.rateLimiter = newRateLimiter(c.MaxRate)
+		}
+	})
+	return c.rateLimiter
+}
@@ -0,0 +1,28 @@
+package llt
+
+import "fmt"
+
+// RefreshRateFeature is LLT's feature for the built-in panel's refresh rate
+const RefreshRateFeature = "refresh-rate"
+
+// GetRefreshRate returns the panel's current refresh rate as LLT names it
+func (c *Client) GetRefreshRate() (string, error) {
+	return c.GetFeature(RefreshRateFeature)
+}
+
+// SetRefreshRate sets the panel's refresh rate
+func (c *Client) SetRefreshRate(rate string) error {
+	return c.SetFeature(RefreshRateFeature, rate)
+}
+
+// RefreshRates returns the refresh rates the panel supports, in LLT's order
+func (c *Client) RefreshRates() ([]string, error) {
+	rates, err := c.listFeatureValues(RefreshRateFeature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refresh rates: %w", err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: no refresh rates reported", ErrFeatureUnsupported)
+	}
+	return rates, nil
+}
@@ -0,0 +1,48 @@
+package llt
+
+import "context"
+
+// CommandRunner starts llt.exe with args and returns its output. A Client
+// without one runs the real llt.exe; tests and programs embedding the
+// client can substitute a fake that returns canned output, so parsing and
+// error handling can be exercised without LLT installed.
+type CommandRunner interface {
+	Run(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// CommandRunnerFunc adapts a function to CommandRunner
+type CommandRunnerFunc func(ctx context.Context, args ...string) ([]byte, error)
+
+// Run calls f
+func (f CommandRunnerFunc) Run(ctx context.Context, args ...string) ([]byte, error) {
+	return f(ctx, args...)
+}
+
+// NewClientWithRunner creates a client whose llt.exe invocations go to
+// runner instead of the executable; path only appears in messages
+func NewClientWithRunner(path string, runner CommandRunner) *Client {
+	return &Client{lltPath: path, Runner: runner}
+}
+
+// execRunner runs the real llt.exe. combined includes stderr in the
+// output, for commands whose errors LLT prints there.
+type execRunner struct {
+	client   *Client
+	combined bool
+}
+
+func (r execRunner) Run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := r.client.command(ctx, args...)
+	if r.combined {
+		return cmd.CombinedOutput()
+	}
+	return cmd.Output()
+}
+
+// runner returns the client's Runner, or the exec-based default
+func (c *Client) runner(combined bool) CommandRunner {
+	if c.Runner != nil {
+		return c.Runner
+	}
+	return execRunner{client: c, combined: combined}
+}
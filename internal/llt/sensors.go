@@ -0,0 +1,39 @@
+package llt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetSensors reads the temperatures and fan speeds LLT reports. LLT prints
+// one "Name: value" pair per line; the values are returned as printed (with
+// their units) since they're only meant for display.
+func (c *Client) GetSensors() (map[string]string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	output, err := c.output(ctx, "sensors")
+	if isUnsupported(output, err) {
+		return nil, fmt.Errorf("sensors: %w", ErrFeatureUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sensors: %w", err)
+	}
+
+	sensors := make(map[string]string)
+	for _, line := range strings.Split(decodeOutput(output), "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			sensors[name] = strings.TrimSpace(value)
+		}
+	}
+
+	if len(sensors) == 0 {
+		return nil, fmt.Errorf("sensors: %w", ErrFeatureUnsupported)
+	}
+
+	return sensors, nil
+}
@@ -0,0 +1,51 @@
+package llt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// cliSettingKey is the LLT settings.json property behind Settings → CLI
+const cliSettingKey = "CLI"
+
+// SettingsPath returns the location of LLT's settings file
+func SettingsPath() string {
+	return filepath.Join(os.Getenv("LOCALAPPDATA"), "LenovoLegionToolkit", "settings.json")
+}
+
+// EnableCLI turns on LLT's CLI setting in its settings file, leaving the
+// other settings untouched. LLT only reads the file at startup, so it has to
+// be restarted for the change to take effect.
+func EnableCLI(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("LLT settings not found at %s (start LLT once to create them)", path)
+		}
+		return fmt.Errorf("failed to read LLT settings: %w", err)
+	}
+
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse LLT settings: %w", err)
+	}
+	settings[cliSettingKey] = json.RawMessage("true")
+
+	data, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode LLT settings: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("%w: permission denied writing %s", ErrElevationRequired, path)
+		}
+		return fmt.Errorf("failed to write LLT settings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package llt
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidateFeatureName checks a feature name given by the user up front,
+// rather than on its first llt.exe call
+func ValidateFeatureName(name string) error {
+	return validateFeatureName(name)
+}
+
+// Longest feature name, value and mode accepted; LLT's own are far shorter
+const (
+	maxFeatureNameLen  = 64
+	maxFeatureValueLen = 256
+	maxModeLen         = 64
+)
+
+// validateFeatureName checks that a feature name looks like an LLT feature
+// identifier ([a-z0-9-]+, not starting with '-') before it's passed on the
+// command line
+func validateFeatureName(name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid feature name: empty")
+	}
+	if len(name) > maxFeatureNameLen {
+		return fmt.Errorf("invalid feature name: longer than %d characters", maxFeatureNameLen)
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid feature name %q: must not start with '-'", name)
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return fmt.Errorf("invalid feature name %q: only lowercase letters, digits and '-' are allowed", name)
+		}
+	}
+	return nil
+}
+
+// validateFeatureValue rejects values containing control characters, which
+// LLT would never accept and which would make for a malformed invocation,
+// values starting with '-', which llt.exe could misread as a flag, and
+// oversized ones
+func validateFeatureValue(value string) error {
+	if len(value) > maxFeatureValueLen {
+		return fmt.Errorf("invalid feature value: longer than %d characters", maxFeatureValueLen)
+	}
+	if strings.HasPrefix(value, "-") {
+		return fmt.Errorf("invalid feature value %q: must not start with '-'", value)
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("invalid feature value %q: contains control characters", value)
+		}
+	}
+	return nil
+}
+
+// sanitizeMode normalizes a power mode name (trimmed, lowercase) and rejects
+// names starting with '-', which llt.exe could misread as a flag, oversized
+// ones, and any character but ASCII letters, digits, '-' and '_'. LLT's mode
+// ids are plain ASCII, so this also stops lookalikes such as a Cyrillic
+// "і" in "quіet" and shell metacharacters.
+func sanitizeMode(mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return "", fmt.Errorf("invalid mode: empty")
+	}
+	if len(mode) > maxModeLen {
+		return "", fmt.Errorf("invalid mode: longer than %d characters", maxModeLen)
+	}
+	if strings.HasPrefix(mode, "-") {
+		return "", fmt.Errorf("invalid mode %q: must not start with '-'", mode)
+	}
+	for _, r := range mode {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return "", fmt.Errorf("invalid mode %q: only letters, digits, '-' and '_' are allowed", mode)
+		}
+	}
+	return mode, nil
+}
@@ -0,0 +1,147 @@
+package llt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateFeatureNameRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"space", "power mode"},
+		{"leading space", " power-mode"},
+		{"double quote", `power-mode"`},
+		{"single quote", "power-mode'"},
+		{"ampersand", "power-mode&calc"},
+		{"pipe", "power-mode|calc"},
+		{"redirect out", "power-mode>out.txt"},
+		{"redirect in", "power-mode<in.txt"},
+		{"leading dash", "-l"},
+		{"flag", "--help"},
+		{"upper case", "Power-Mode"},
+		{"equals", "power-mode=quiet"},
+		{"newline", "power-mode\nquiet"},
+		{"overlong", strings.Repeat("a", maxFeatureNameLen+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateFeatureName(tt.input); err == nil {
+				t.Errorf("validateFeatureName(%q) accepted it", tt.input)
+			}
+		})
+	}
+}
+
+func TestValidateFeatureNameAccepts(t *testing.T) {
+	for _, name := range []string{"power-mode", "refresh-rate", "hybrid-mode", "a", strings.Repeat("a", maxFeatureNameLen)} {
+		if err := validateFeatureName(name); err != nil {
+			t.Errorf("validateFeatureName(%q): %v", name, err)
+		}
+	}
+}
+
+func TestValidateFeatureValueRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"newline", "on\noff"},
+		{"carriage return", "on\r"},
+		{"nul", "on\x00"},
+		{"escape", "\x1b[2J"},
+		{"tab", "on\toff"},
+		{"leading dash", "-l"},
+		{"flag", "--help"},
+		{"overlong", strings.Repeat("a", maxFeatureValueLen+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateFeatureValue(tt.input); err == nil {
+				t.Errorf("validateFeatureValue(%q) accepted it", tt.input)
+			}
+		})
+	}
+}
+
+func TestValidateFeatureValueAccepts(t *testing.T) {
+	// Values reach llt.exe as a single argument without a shell, so spaces,
+	// quotes and shell metacharacters can't start anything else; LLT decides
+	// whether it knows the value
+	for _, value := range []string{"", "on", "165", "1920x1080", "a b", `"quoted"`, "a&b|c<d>e", strings.Repeat("a", maxFeatureValueLen)} {
+		if err := validateFeatureValue(value); err != nil {
+			t.Errorf("validateFeatureValue(%q): %v", value, err)
+		}
+	}
+}
+
+func TestSanitizeModeRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"blank", " \t "},
+		{"leading dash", "-quiet"},
+		{"flag", "--help"},
+		{"inner space", "quiet performance"},
+		{"ampersand", "quiet&calc"},
+		{"pipe", "quiet|calc"},
+		{"semicolon", "quiet;calc"},
+		{"redirect", "quiet>out.txt"},
+		{"backtick", "quiet`calc`"},
+		{"dollar", "$(calc)"},
+		{"percent", "%COMSPEC%"},
+		{"quote", `quiet"`},
+		{"newline", "quiet\nperformance"},
+		{"nul", "quiet\x00"},
+		{"escape", "\x1b[2Jquiet"},
+		{"bell", "quiet\a"},
+		{"cyrillic i lookalike", "quіet"},
+		{"greek omicron lookalike", "perfοrmance"},
+		{"fullwidth letters", "ｑｕｉｅｔ"},
+		{"zero width space", "qui​et"},
+		{"right-to-left override", "quiet‮"},
+		{"path", `..\quiet`},
+		{"equals", "power-mode=quiet"},
+		{"oversized", strings.Repeat("q", maxModeLen+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := sanitizeMode(tt.input); err == nil {
+				t.Errorf("sanitizeMode(%q) = %q, want an error", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestSanitizeModeNormalizes(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"quiet", "quiet"},
+		{"  Performance\r\n", "performance"},
+		{"GODMODE", "godmode"},
+		{"my-mode_2", "my-mode_2"},
+		{strings.Repeat("q", maxModeLen), strings.Repeat("q", maxModeLen)},
+	}
+	for _, tt := range tests {
+		got, err := sanitizeMode(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("sanitizeMode(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestSetModeRejectsBeforeRunning(t *testing.T) {
+	client, calls := newFakeClient(t, nil)
+
+	if err := client.SetMode("quiet & calc"); err == nil {
+		t.Error("SetMode accepted a mode with shell metacharacters")
+	}
+	if len(*calls) != 0 {
+		t.Errorf("SetMode started llt.exe %d times for an invalid mode", len(*calls))
+	}
+}
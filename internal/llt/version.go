@@ -0,0 +1,34 @@
+//go:build windows
+
+package llt
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Version returns the file version of the installed llt.exe (e.g. 2.14.0.0),
+// read from its version resource so LLT doesn't need to be running
+func (c *Client) Version() (string, error) {
+	size, err := windows.GetFileVersionInfoSize(c.lltPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read LLT version: %w", err)
+	}
+
+	info := make([]byte, size)
+	if err := windows.GetFileVersionInfo(c.lltPath, 0, size, unsafe.Pointer(&info[0])); err != nil {
+		return "", fmt.Errorf("failed to read LLT version: %w", err)
+	}
+
+	var fixed *windows.VS_FIXEDFILEINFO
+	var fixedLen uint32
+	if err := windows.VerQueryValue(unsafe.Pointer(&info[0]), `\`, unsafe.Pointer(&fixed), &fixedLen); err != nil {
+		return "", fmt.Errorf("failed to read LLT version: %w", err)
+	}
+
+	return fmt.Sprintf("%d.%d.%d.%d",
+		fixed.FileVersionMS>>16, fixed.FileVersionMS&0xffff,
+		fixed.FileVersionLS>>16, fixed.FileVersionLS&0xffff), nil
+}
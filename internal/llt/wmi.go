@@ -0,0 +1,62 @@
+package llt
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Power mode read sources for Client.ReadSource
+const (
+	ReadSourceCLI  = "cli"  // llt.exe only (default)
+	ReadSourceWMI  = "wmi"  // Lenovo WMI only
+	ReadSourceAuto = "auto" // llt.exe, falling back to WMI
+)
+
+// IsValidReadSource reports whether source is a supported read source
+func IsValidReadSource(source string) bool {
+	switch source {
+	case ReadSourceCLI, ReadSourceWMI, ReadSourceAuto:
+		return true
+	}
+	return false
+}
+
+// wmiPowerModeScript queries the same Lenovo GameZone WMI method LLT uses to
+// read the power mode ("smart fan mode")
+const wmiPowerModeScript = "(Get-CimInstance -Namespace root/WMI -ClassName LENOVO_GAMEZONE_DATA | " +
+	"Invoke-CimMethod -MethodName GetSmartFanMode).Data"
+
+// wmiPowerModes maps GetSmartFanMode results to LLT power mode names
+var wmiPowerModes = map[string]string{
+	"1":   "quiet",
+	"2":   "balance",
+	"3":   "performance",
+	"255": "godmode",
+}
+
+// getModeWMI reads the current power mode from the Lenovo WMI interface,
+// bypassing llt.exe entirely
+func (c *Client) getModeWMI() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", wmiPowerModeScript)
+
+	hideWindow(cmd)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read power mode via WMI: %w", err)
+	}
+
+	value := strings.TrimSpace(string(output))
+	mode, ok := wmiPowerModes[value]
+	if !ok {
+		return "", fmt.Errorf("unexpected WMI power mode value %q", value)
+	}
+
+	return mode, nil
+}
@@ -1,8 +1,13 @@
 package modes
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
 // PowerMode represents a Lenovo Legion Toolkit power mode
@@ -20,41 +25,123 @@ type ModeMetadata struct {
 	Name        string
 	Description string
 	IconPath    string
-	Color       string // Future use
+	Color       string // #RRGGBB, drawn as the toast's accent strip
+	Symbol      string // Compact indicator for tiny displays (status --short)
+
+	// ToastPosition overrides the global toast position for this mode ("" keeps it)
+	ToastPosition string
+
+	// ToastMessage is a text/template for the mode change toast ("" keeps
+	// the default message)
+	ToastMessage string
 }
 
 // Manager handles power mode operations
 type Manager struct {
-	sequence []PowerMode
+	sequence  []PowerMode
+	custom    map[PowerMode]ModeMetadata
+	iconTheme string
+	fallback  PowerMode // next mode when the current one is unknown; "" means first
+
+	// configured is the built-in or user-chosen cycle, which SetSequence
+	// doesn't replace
+	configured []PowerMode
 }
 
 // NewManager creates a new power mode manager
 func NewManager() *Manager {
+	defaultCycle := []PowerMode{Quiet, Balance, Performance}
 	return &Manager{
-		sequence: []PowerMode{Quiet, Balance, Performance},
+		sequence:   defaultCycle,
+		configured: defaultCycle,
 	}
 }
 
-// GetNextMode returns the next power mode in the sequence
-func (m *Manager) GetNextMode(current PowerMode) PowerMode {
-	currentIndex := -1
-	for i, mode := range m.sequence {
-		if mode == current {
-			currentIndex = i
-			break
+// NewManagerFromModes creates a manager whose sequence is the modes a
+// device offers, as listed by LLT (see SetSequence)
+func NewManagerFromModes(available []string) *Manager {
+	m := NewManager()
+	m.SetSequence(available)
+	return m
+}
+
+// SetSequence replaces the default sequence with available, the modes LLT
+// lists for this device, in LLT's order. Names are trimmed, lowercased and
+// deduplicated. GodMode stays out of the default cycle as usual. If nothing
+// is left, the built-in sequence is kept and false is returned. The
+// configured cycle is kept for a current mode LLT didn't list (see CycleFor).
+func (m *Manager) SetSequence(available []string) bool {
+	var sequence []PowerMode
+	for _, name := range available {
+		mode := PowerMode(strings.ToLower(strings.TrimSpace(name)))
+		if mode == "" || mode == GodMode || slices.Contains(sequence, mode) {
+			continue
 		}
+		sequence = append(sequence, mode)
+	}
+	if len(sequence) == 0 {
+		return false
+	}
+	m.sequence = sequence
+	return true
+}
+
+// SetCycle replaces the sequence with one the user chose, e.g. the config
+// file's "sequence". Unlike SetSequence it takes the modes as given, GodMode
+// included, so the caller should check them with IsValidMode first. An
+// empty cycle keeps the current sequence.
+func (m *Manager) SetCycle(cycle []PowerMode) {
+	if len(cycle) > 0 {
+		m.sequence = slices.Clone(cycle)
+		m.configured = slices.Clone(cycle)
+	}
+}
+
+// ConfiguredCycle returns the cycle from SetCycle, or the built-in one,
+// regardless of the modes SetSequence took from LLT
+func (m *Manager) ConfiguredCycle() []PowerMode {
+	return slices.Clone(m.configured)
+}
+
+// CycleFor returns the cycle that moving on from current goes through:
+// allowedModes when given, else the default sequence. When the sequence
+// came from LLT's list and current isn't in it but is in the configured
+// cycle (LLT's list and its current mode disagree), that cycle is used
+// instead, so toggling stays predictable.
+func (m *Manager) CycleFor(current PowerMode, allowedModes []PowerMode) []PowerMode {
+	if len(allowedModes) > 0 {
+		return allowedModes
+	}
+	if !slices.Contains(m.sequence, current) && slices.Contains(m.configured, current) {
+		return m.configured
 	}
+	return m.sequence
+}
+
+// GetNextMode returns the next power mode in the cycle from CycleFor,
+// wrapping from the last mode back to the first. A current mode that isn't
+// in it (e.g. godmode) lands on the unknown-mode fallback, the first mode by
+// default.
+func (m *Manager) GetNextMode(current PowerMode) PowerMode {
+	cycle := m.CycleFor(current, nil)
+	currentIndex := slices.Index(cycle, current)
 
 	if currentIndex == -1 {
-		// Invalid current mode, default to first
-		return m.sequence[0]
+		// Invalid current mode, use the configured fallback or the first
+		if slices.Contains(cycle, m.fallback) {
+			return m.fallback
+		}
+		return cycle[0]
 	}
 
-	nextIndex := (currentIndex + 1) % len(m.sequence)
-	return m.sequence[nextIndex]
+	nextIndex := (currentIndex + 1) % len(cycle)
+	return cycle[nextIndex]
 }
 
-// GetNextModeFromList returns the next power mode from the provided list
+// GetNextModeFromList returns the next power mode from the provided list,
+// wrapping at the end. A current mode not in the list lands on its first
+// entry. An empty list means no --modes restriction was given, so it
+// intentionally cycles the full default sequence via GetNextMode.
 func (m *Manager) GetNextModeFromList(current PowerMode, allowedModes []PowerMode) PowerMode {
 	if len(allowedModes) == 0 {
 		return m.GetNextMode(current)
@@ -77,43 +164,218 @@ func (m *Manager) GetNextModeFromList(current PowerMode, allowedModes []PowerMod
 	return allowedModes[nextIndex]
 }
 
-// IsValidMode checks if the given mode string is valid
+// GetPrevModeFromList mirrors GetNextModeFromList backwards: it returns the
+// mode before current in the provided list (the cycle from CycleFor when
+// empty), wrapping from the first back to the last. A current mode not in
+// the list lands on its last entry.
+func (m *Manager) GetPrevModeFromList(current PowerMode, allowedModes []PowerMode) PowerMode {
+	cycle := m.CycleFor(current, allowedModes)
+
+	currentIndex := slices.Index(cycle, current)
+	if currentIndex == -1 {
+		return cycle[len(cycle)-1]
+	}
+
+	prevIndex := (currentIndex + len(cycle) - 1) % len(cycle)
+	return cycle[prevIndex]
+}
+
+// Cycle returns the modes GetNextModeFromList cycles through, in order:
+// allowedModes, or the default sequence when it's empty
+func (m *Manager) Cycle(allowedModes []PowerMode) []PowerMode {
+	if len(allowedModes) == 0 {
+		return slices.Clone(m.sequence)
+	}
+	return slices.Clone(allowedModes)
+}
+
+// CyclePosition returns the 1-based position of mode within the cycle used
+// by GetNextModeFromList (allowedModes, or the default sequence when empty)
+// along with the cycle length. Position is 0 if mode isn't in the cycle.
+func (m *Manager) CyclePosition(mode PowerMode, allowedModes []PowerMode) (int, int) {
+	cycle := m.Cycle(allowedModes)
+
+	for i, candidate := range cycle {
+		if candidate == mode {
+			return i + 1, len(cycle)
+		}
+	}
+
+	return 0, len(cycle)
+}
+
+// ModeAt returns the mode at the 1-based position index in the default
+// sequence, e.g. 2 is balance in the built-in quiet, balance, performance
+// cycle. An out-of-range index is an error that names the valid range.
+func (m *Manager) ModeAt(index int) (PowerMode, error) {
+	if index < 1 || index > len(m.sequence) {
+		return "", fmt.Errorf("mode index %d out of range (1-%d)", index, len(m.sequence))
+	}
+	return m.sequence[index-1], nil
+}
+
+// SetCustomMetadata registers user-defined metadata (typically from the
+// config file). Entries override the built-in metadata field by field and
+// make modes outside the default sequence (e.g. custom) valid.
+// Relative icon paths are resolved against the assets directory.
+func (m *Manager) SetCustomMetadata(custom map[PowerMode]ModeMetadata) {
+	m.custom = custom
+}
+
+// SetUnknownFallback sets the mode GetNextMode moves to when the current
+// mode isn't in the cycle. Modes outside the cycle (or "") fall back to its
+// first mode.
+func (m *Manager) SetUnknownFallback(mode PowerMode) {
+	m.fallback = mode
+}
+
+// SetIconTheme selects an icon set under assets/icons/<theme>/. An empty
+// theme, one that isn't a plain directory name (see IsValidIconTheme), or
+// one whose directory doesn't exist uses assets/icons/.
+func (m *Manager) SetIconTheme(theme string) {
+	m.iconTheme = theme
+}
+
+// IsValidIconTheme reports whether theme names a directory directly under
+// assets/icons/, so values like "../x" can't point icons elsewhere
+func IsValidIconTheme(theme string) bool {
+	if theme == "" {
+		return true
+	}
+	return theme != "." && theme != ".." && !strings.ContainsAny(theme, `/\:`) && filepath.Base(theme) == theme
+}
+
+// iconDir returns the directory holding the built-in mode icons
+func (m *Manager) iconDir(baseDir string) string {
+	iconDir := filepath.Join(baseDir, "assets", "icons")
+	if m.iconTheme == "" || !IsValidIconTheme(m.iconTheme) {
+		return iconDir
+	}
+
+	themed := filepath.Join(iconDir, m.iconTheme)
+	if info, err := os.Stat(themed); err == nil && info.IsDir() {
+		return themed
+	}
+	return iconDir
+}
+
+// IsBuiltinMode reports whether mode is one of the built-in modes (quiet,
+// balance, performance) that the helper knows LLT accepts
+func (m *Manager) IsBuiltinMode(mode string) bool {
+	switch PowerMode(mode) {
+	case Quiet, Balance, Performance:
+		return true
+	}
+	return false
+}
+
+// IsValidMode checks if the given mode string is valid: a mode in the
+// sequence, GodMode, or a configured custom mode
 func (m *Manager) IsValidMode(mode string) bool {
+	if PowerMode(mode) == GodMode {
+		return true
+	}
 	for _, pm := range m.sequence {
 		if string(pm) == mode {
 			return true
 		}
 	}
-	return false
+	_, configured := m.custom[PowerMode(mode)]
+	return configured
+}
+
+// Modes returns every known mode: the cycle sequence, then GodMode (which
+// stays out of the default cycle but can be set or added with --modes),
+// then any configured custom modes, sorted by id
+func (m *Manager) Modes() []PowerMode {
+	all := append([]PowerMode{}, m.sequence...)
+	if !slices.Contains(all, GodMode) {
+		all = append(all, GodMode)
+	}
+
+	var custom []PowerMode
+	for mode := range m.custom {
+		if !slices.Contains(all, mode) {
+			custom = append(custom, mode)
+		}
+	}
+	slices.Sort(custom)
+
+	return append(all, custom...)
 }
 
 // GetModeMetadata returns metadata for the given power mode
 func (m *Manager) GetModeMetadata(mode PowerMode) ModeMetadata {
 	// Find the assets directory relative to the executable
 	baseDir := findAssetsDir()
+	iconDir := m.iconDir(baseDir)
 
 	metadata := map[PowerMode]ModeMetadata{
 		Quiet: {
 			Name:        "Quiet",
 			Description: "Silent operation with minimal power consumption",
-			IconPath:    filepath.Join(baseDir, "assets", "icons", "quiet.png"),
+			IconPath:    filepath.Join(iconDir, "quiet.png"),
 			Color:       "#4A90E2",
+			Symbol:      "Q",
 		},
 		Balance: {
 			Name:        "Balance",
 			Description: "Balanced performance and efficiency",
-			IconPath:    filepath.Join(baseDir, "assets", "icons", "balance.png"),
+			IconPath:    filepath.Join(iconDir, "balance.png"),
 			Color:       "#7ED321",
+			Symbol:      "B",
 		},
 		Performance: {
 			Name:        "Performance",
 			Description: "Increased power for better performance",
-			IconPath:    filepath.Join(baseDir, "assets", "icons", "performance.png"),
+			IconPath:    filepath.Join(iconDir, "performance.png"),
 			Color:       "#F5A623",
+			Symbol:      "P",
+		},
+		GodMode: {
+			Name:        "GodMode",
+			Description: "Custom CPU/GPU power limits and fan curves set in LLT",
+			IconPath:    filepath.Join(iconDir, "godmode.png"),
+			Color:       "#9013FE",
+			Symbol:      "G",
 		},
 	}
 
-	if meta, exists := metadata[mode]; exists {
+	meta, exists := metadata[mode]
+	if custom, ok := m.custom[mode]; ok {
+		if !exists {
+			meta = ModeMetadata{Name: string(mode), Description: "Custom power mode", Color: "#000000"}
+		}
+		if custom.Name != "" {
+			meta.Name = custom.Name
+		}
+		if custom.Description != "" {
+			meta.Description = custom.Description
+		}
+		if custom.IconPath != "" {
+			meta.IconPath = custom.IconPath
+			if !filepath.IsAbs(meta.IconPath) {
+				meta.IconPath = filepath.Join(baseDir, meta.IconPath)
+			}
+		}
+		if custom.Color != "" {
+			meta.Color = custom.Color
+		}
+		if custom.ToastPosition != "" {
+			meta.ToastPosition = custom.ToastPosition
+		}
+		if custom.ToastMessage != "" {
+			meta.ToastMessage = custom.ToastMessage
+		}
+		if custom.Symbol != "" {
+			meta.Symbol = custom.Symbol
+		} else if !exists {
+			meta.Symbol = defaultSymbol(meta.Name)
+		}
+		return meta
+	}
+
+	if exists {
 		return meta
 	}
 
@@ -123,21 +385,56 @@ func (m *Manager) GetModeMetadata(mode PowerMode) ModeMetadata {
 		Description: "Unknown power mode",
 		IconPath:    "",
 		Color:       "#000000",
+		Symbol:      defaultSymbol(string(mode)),
 	}
 }
 
-// findAssetsDir locates the assets directory relative to the executable
-func findAssetsDir() string {
-	// Try to get executable path
-	exePath, err := os.Executable()
-	if err != nil {
-		// Fallback to current working directory
-		cwd, _ := os.Getwd()
-		return cwd
+// defaultSymbol returns the uppercased first letter of a mode name
+func defaultSymbol(name string) string {
+	for _, r := range name {
+		return strings.ToUpper(string(r))
 	}
+	return "?"
+}
+
+// assetsProbeTimeout bounds the directory checks in findAssetsDir, which can
+// hang when the executable lives on a slow network share
+const assetsProbeTimeout = 2 * time.Second
 
-	exeDir := filepath.Dir(exePath)
+var (
+	assetsDirOnce sync.Once
+	assetsDir     string
+)
+
+// findAssetsDir locates the assets directory relative to the executable. The
+// result is cached, and if the checks don't finish within assetsProbeTimeout
+// the executable directory is used.
+func findAssetsDir() string {
+	assetsDirOnce.Do(func() {
+		// Try to get executable path
+		exePath, err := os.Executable()
+		if err != nil {
+			// Fallback to current working directory
+			assetsDir, _ = os.Getwd()
+			return
+		}
+
+		exeDir := filepath.Dir(exePath)
+		found := make(chan string, 1)
+		go func() { found <- probeAssetsDir(exeDir) }()
+
+		select {
+		case assetsDir = <-found:
+		case <-time.After(assetsProbeTimeout):
+			fmt.Fprintf(os.Stderr, "Warning: timed out looking for assets near %s, using the executable directory\n", exeDir)
+			assetsDir = exeDir
+		}
+	})
+	return assetsDir
+}
 
+// probeAssetsDir checks the usual places for the assets directory
+func probeAssetsDir(exeDir string) string {
 	// Check if assets exists in executable directory
 	assetsPath := filepath.Join(exeDir, "assets")
 	if _, err := os.Stat(assetsPath); err == nil {
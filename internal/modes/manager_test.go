@@ -0,0 +1,209 @@
+package modes
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetNextMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence []PowerMode
+		fallback PowerMode
+		current  PowerMode
+		want     PowerMode
+	}{
+		{"advances", nil, "", Quiet, Balance},
+		{"wraps around", nil, "", Performance, Quiet},
+		{"godmode lands on first", nil, "", GodMode, Quiet},
+		{"invalid lands on first", nil, "", "turbo", Quiet},
+		{"invalid lands on fallback", nil, Balance, "turbo", Balance},
+		{"fallback outside sequence ignored", nil, GodMode, "turbo", Quiet},
+		{"single mode", []PowerMode{Performance}, "", Performance, Performance},
+		{"custom cycle", []PowerMode{Performance, GodMode}, "", Performance, GodMode},
+		{"custom cycle wraps", []PowerMode{Performance, GodMode}, "", GodMode, Performance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager()
+			m.SetCycle(tt.sequence)
+			m.SetUnknownFallback(tt.fallback)
+			if got := m.GetNextMode(tt.current); got != tt.want {
+				t.Errorf("GetNextMode(%q) = %q, want %q", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNextModeFromList(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []PowerMode
+		current PowerMode
+		want    PowerMode
+	}{
+		{"empty list uses sequence", nil, Balance, Performance},
+		{"empty list wraps sequence", nil, Performance, Quiet},
+		{"advances", []PowerMode{Quiet, Performance}, Quiet, Performance},
+		{"wraps around", []PowerMode{Quiet, Performance}, Performance, Quiet},
+		{"single mode", []PowerMode{GodMode}, GodMode, GodMode},
+		{"current not in list", []PowerMode{Performance, GodMode}, Balance, Performance},
+		{"invalid current", []PowerMode{Balance, Quiet}, "", Balance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewManager().GetNextModeFromList(tt.current, tt.allowed); got != tt.want {
+				t.Errorf("GetNextModeFromList(%q, %q) = %q, want %q", tt.current, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPrevModeFromList(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []PowerMode
+		current PowerMode
+		want    PowerMode
+	}{
+		{"empty list uses sequence", nil, Balance, Quiet},
+		{"empty list wraps sequence", nil, Quiet, Performance},
+		{"steps back", []PowerMode{Quiet, Performance}, Performance, Quiet},
+		{"wraps around", []PowerMode{Quiet, Performance}, Quiet, Performance},
+		{"single mode", []PowerMode{GodMode}, GodMode, GodMode},
+		{"current not in list", []PowerMode{Performance, GodMode}, Balance, GodMode},
+		{"invalid current", []PowerMode{Balance, Quiet}, "turbo", Quiet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewManager().GetPrevModeFromList(tt.current, tt.allowed); got != tt.want {
+				t.Errorf("GetPrevModeFromList(%q, %q) = %q, want %q", tt.current, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSequence(t *testing.T) {
+	m := NewManager()
+	if m.SetSequence([]string{"", "  ", "godmode"}) {
+		t.Error("SetSequence with no usable modes returned true")
+	}
+	if got := m.GetNextMode(Performance); got != Quiet {
+		t.Errorf("built-in sequence not kept: GetNextMode(performance) = %q", got)
+	}
+
+	if !m.SetSequence([]string{" Balance", "PERFORMANCE", "balance", "GodMode"}) {
+		t.Fatal("SetSequence returned false")
+	}
+	if got := m.GetNextMode(Performance); got != Balance {
+		t.Errorf("GetNextMode(performance) = %q, want balance", got)
+	}
+	if got := m.GetNextMode("turbo"); got != Balance {
+		t.Errorf("GetNextMode(turbo) = %q, want balance, the first offered mode", got)
+	}
+}
+
+func TestCycleFor(t *testing.T) {
+	m := NewManagerFromModes([]string{"quiet", "performance"})
+	listed := []PowerMode{Quiet, Performance}
+	configured := []PowerMode{Quiet, Balance, Performance}
+
+	tests := []struct {
+		name    string
+		current PowerMode
+		allowed []PowerMode
+		want    []PowerMode
+	}{
+		{"listed mode", Quiet, nil, listed},
+		{"mode LLT didn't list", Balance, nil, configured},
+		{"unknown mode", "turbo", nil, listed},
+		{"godmode", GodMode, nil, listed},
+		{"allowed modes win", Balance, []PowerMode{Balance, GodMode}, []PowerMode{Balance, GodMode}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.CycleFor(tt.current, tt.allowed); !slices.Equal(got, tt.want) {
+				t.Errorf("CycleFor(%q, %q) = %q, want %q", tt.current, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrentNotListedUsesConfiguredCycle(t *testing.T) {
+	m := NewManagerFromModes([]string{"quiet", "performance"})
+
+	if got := m.GetNextMode(Balance); got != Performance {
+		t.Errorf("GetNextMode(balance) = %q, want performance from the configured cycle", got)
+	}
+	if got := m.GetPrevModeFromList(Balance, nil); got != Quiet {
+		t.Errorf("GetPrevModeFromList(balance) = %q, want quiet from the configured cycle", got)
+	}
+
+	// A config file cycle replaces the built-in one as the fallback
+	m = NewManager()
+	m.SetCycle([]PowerMode{Performance, Quiet})
+	m.SetSequence([]string{"quiet", "balance"})
+	if got := m.GetNextMode(Performance); got != Quiet {
+		t.Errorf("GetNextMode(performance) = %q, want quiet from the config cycle", got)
+	}
+	if got := m.ConfiguredCycle(); !slices.Equal(got, []PowerMode{Performance, Quiet}) {
+		t.Errorf("ConfiguredCycle = %q, want the config cycle", got)
+	}
+}
+
+func TestCustomModeMetadata(t *testing.T) {
+	m := NewManager()
+	m.SetCustomMetadata(map[PowerMode]ModeMetadata{
+		"turbo": {Name: "Turbo", Description: "Maximum clocks", Color: "#FF0000", ToastPosition: "top"},
+		"eco":   {},
+	})
+
+	meta := m.GetModeMetadata("turbo")
+	if meta.Name != "Turbo" || meta.Description != "Maximum clocks" || meta.Color != "#FF0000" {
+		t.Errorf("GetModeMetadata(turbo) = %+v, want the configured fields", meta)
+	}
+	if meta.ToastPosition != "top" || meta.Symbol != "T" {
+		t.Errorf("GetModeMetadata(turbo) = %+v, want toast position top and symbol T", meta)
+	}
+
+	eco := m.GetModeMetadata("eco")
+	if eco.Name != "eco" || eco.Description != "Custom power mode" || eco.Symbol != "E" {
+		t.Errorf("GetModeMetadata(eco) = %+v, want custom defaults", eco)
+	}
+
+	for _, mode := range []string{"turbo", "eco"} {
+		if !m.IsValidMode(mode) {
+			t.Errorf("IsValidMode(%q) = false for a configured custom mode", mode)
+		}
+		if m.IsBuiltinMode(mode) {
+			t.Errorf("IsBuiltinMode(%q) = true for a custom mode", mode)
+		}
+	}
+	if m.IsValidMode("warp") {
+		t.Error("IsValidMode(warp) = true for an unconfigured mode")
+	}
+}
+
+func TestCustomMetadataOverridesBuiltin(t *testing.T) {
+	m := NewManager()
+	m.SetCustomMetadata(map[PowerMode]ModeMetadata{Quiet: {Name: "Silent"}})
+
+	meta := m.GetModeMetadata(Quiet)
+	if meta.Name != "Silent" {
+		t.Errorf("Name = %q, want Silent", meta.Name)
+	}
+	// Fields left empty keep the built-in values
+	if meta.Color != "#4A90E2" || meta.Symbol != "Q" || meta.Description == "Custom power mode" {
+		t.Errorf("GetModeMetadata(quiet) = %+v, want built-in fields kept", meta)
+	}
+}
+
+func TestModesListsCustom(t *testing.T) {
+	m := NewManager()
+	m.SetCustomMetadata(map[PowerMode]ModeMetadata{"turbo": {}, "eco": {}, Quiet: {}})
+
+	want := []PowerMode{Quiet, Balance, Performance, GodMode, "eco", "turbo"}
+	if got := m.Modes(); !slices.Equal(got, want) {
+		t.Errorf("Modes = %q, want %q", got, want)
+	}
+}
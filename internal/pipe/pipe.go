@@ -0,0 +1,146 @@
+//go:build windows
+
+package pipe
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// DefaultName is the pipe the serve command listens on
+const DefaultName = `\\.\pipe\llt-helper`
+
+const bufferSize = 4096
+
+var procPeekNamedPipe = windows.NewLazySystemDLL("kernel32.dll").NewProc("PeekNamedPipe")
+
+// ErrClosed is returned by Accept once the listener has been closed
+var ErrClosed = errors.New("pipe listener closed")
+
+// Listener accepts clients on a local named pipe. Each Accept creates a new
+// pipe instance, so clients already connected stay connected while the next
+// one is awaited.
+type Listener struct {
+	name string
+
+	mu      sync.Mutex
+	pending windows.Handle // instance waiting in Accept, if any
+	closed  bool
+}
+
+// Listen prepares a named pipe listener. No pipe instance exists until
+// Accept is called.
+func Listen(name string) *Listener {
+	return &Listener{name: name}
+}
+
+// Accept creates a pipe instance and blocks until a client connects to it
+func (l *Listener) Accept() (*Conn, error) {
+	name, err := windows.UTF16PtrFromString(l.name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipe name: %w", err)
+	}
+
+	// Overlapped, so a Conn can be read and written from different
+	// goroutines at once (os.NewFile puts it on the runtime's poller)
+	handle, err := windows.CreateNamedPipe(name,
+		windows.PIPE_ACCESS_DUPLEX|windows.FILE_FLAG_OVERLAPPED,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES, bufferSize, bufferSize, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe %s: %w", l.name, err)
+	}
+
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		windows.CloseHandle(handle)
+		return nil, ErrClosed
+	}
+	l.pending = handle
+	l.mu.Unlock()
+
+	// ERROR_PIPE_CONNECTED means the client connected before we started waiting
+	err = connect(handle)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, ErrClosed // Close already released the instance
+	}
+	l.pending = 0
+	if err != nil && !errors.Is(err, windows.ERROR_PIPE_CONNECTED) {
+		windows.CloseHandle(handle)
+		return nil, fmt.Errorf("failed to accept pipe client: %w", err)
+	}
+
+	return &Conn{handle: handle, file: os.NewFile(uintptr(handle), l.name)}, nil
+}
+
+// connect waits for a client to connect to an overlapped pipe instance
+func connect(handle windows.Handle) error {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create pipe event: %w", err)
+	}
+	defer windows.CloseHandle(event)
+
+	overlapped := windows.Overlapped{HEvent: event}
+	err = windows.ConnectNamedPipe(handle, &overlapped)
+	if !errors.Is(err, windows.ERROR_IO_PENDING) {
+		return err
+	}
+	var done uint32
+	return windows.GetOverlappedResult(handle, &overlapped, &done, true)
+}
+
+// Close releases the pipe instance waiting for a client, so the pipe name
+// is free again, and makes further Accept calls fail with ErrClosed.
+// Connections already accepted are closed separately.
+func (l *Listener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	if l.pending == 0 {
+		return nil
+	}
+	// Wake Accept, which is waiting on the instance
+	windows.CancelIoEx(l.pending, nil)
+	err := windows.CloseHandle(l.pending)
+	l.pending = 0
+	return err
+}
+
+// Conn is one connected pipe client
+type Conn struct {
+	handle windows.Handle
+	file   *os.File
+}
+
+func (c *Conn) Read(p []byte) (int, error) {
+	return c.file.Read(p)
+}
+
+func (c *Conn) Write(p []byte) (int, error) {
+	return c.file.Write(p)
+}
+
+// Connected reports whether the client is still attached, without blocking
+func (c *Conn) Connected() bool {
+	var available uint32
+	ret, _, _ := procPeekNamedPipe.Call(uintptr(c.handle), 0, 0, 0, uintptr(unsafe.Pointer(&available)), 0)
+	return ret != 0
+}
+
+// Close disconnects the client and releases the pipe instance
+func (c *Conn) Close() error {
+	windows.FlushFileBuffers(c.handle)
+	windows.DisconnectNamedPipe(c.handle)
+	return c.file.Close()
+}
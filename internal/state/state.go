@@ -0,0 +1,87 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is helper-local data persisted between invocations. It never holds
+// device settings, only what the helper needs to remember about itself.
+type State struct {
+	// LastChange is when the helper last changed the power mode
+	LastChange time.Time `json:"lastChange,omitempty"`
+
+	// LastToast is when the helper last showed a mode-change toast
+	LastToast time.Time `json:"lastToast,omitempty"`
+
+	// LastMode is the last power mode the helper successfully set
+	LastMode string `json:"lastMode,omitempty"`
+
+	// LastModeAt is when LastMode was set
+	LastModeAt time.Time `json:"lastModeAt,omitempty"`
+
+	// LockedMode is the mode pinned by `lock`; toggle/set refuse to change
+	// it until `unlock`
+	LockedMode string `json:"lockedMode,omitempty"`
+
+	// PendingRevert is a revert scheduled by set --revert-after that hasn't
+	// happened yet
+	PendingRevert *PendingRevert `json:"pendingRevert,omitempty"`
+}
+
+// PendingRevert switches back to Mode at At, provided the mode is still
+// From (the one set --revert-after applied) by then
+type PendingRevert struct {
+	Mode string    `json:"mode"`
+	From string    `json:"from"`
+	At   time.Time `json:"at"`
+}
+
+// DefaultPath returns the default state file location (%LOCALAPPDATA%\llt-helper\state.json)
+func DefaultPath() string {
+	base := os.Getenv("LOCALAPPDATA")
+	if base == "" {
+		base = filepath.Join(os.Getenv("USERPROFILE"), "AppData", "Local")
+	}
+	return filepath.Join(base, "llt-helper", "state.json")
+}
+
+// Load reads the state file at path. A missing file yields an empty state.
+func Load(path string) (*State, error) {
+	st := &State{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return st, fmt.Errorf("failed to read state %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, st); err != nil {
+		return &State{}, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+
+	return st, nil
+}
+
+// Save writes the state to path, creating the parent directory if needed
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write state %s: %w", path, err)
+	}
+
+	return nil
+}
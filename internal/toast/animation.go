@@ -0,0 +1,99 @@
+//go:build windows
+
+package toast
+
+import (
+	"time"
+)
+
+// OSD animation styles
+const (
+	AnimationNone  = "none"
+	AnimationFade  = "fade"
+	AnimationSlide = "slide"
+)
+
+const (
+	closeTimerID = 1
+	animTimerID  = 2
+
+	animFrame     = 15 * time.Millisecond
+	animDuration  = 200 * time.Millisecond
+	slideDistance = 40  // pixels travelled by the slide animation
+	osdAlpha      = 220 // resting opacity (~86%)
+
+	SWP_NOACTIVATE = 0x0010
+)
+
+// IsValidAnimation reports whether name is a supported animation style
+func IsValidAnimation(name string) bool {
+	switch name {
+	case AnimationNone, AnimationFade, AnimationSlide:
+		return true
+	}
+	return false
+}
+
+// animationState tracks the OSD's entrance or exit animation
+type animationState struct {
+	kind    string
+	closing bool
+	start   time.Time
+	x, y    int32 // resting window position
+}
+
+// initialPlacement returns where the window should be created and the
+// opacity it starts with, given its resting position
+func initialPlacement(kind string, x, y int32) (int32, int32, uintptr) {
+	switch kind {
+	case AnimationFade:
+		return x, y, 0
+	case AnimationSlide:
+		return x, y + slideDistance, osdAlpha
+	}
+	return x, y, osdAlpha
+}
+
+// killTimers stops the close, animation and progress timers, whichever
+// are running
+func killTimers(hwnd uintptr) {
+	procKillTimer.Call(hwnd, closeTimerID)
+	procKillTimer.Call(hwnd, animTimerID)
+	procKillTimer.Call(hwnd, progressTimerID)
+}
+
+// startAnimation begins the entrance (closing=false) or exit animation
+func (w *osdWindow) startAnimation(closing bool) {
+	w.anim.closing = closing
+	w.anim.start = time.Now()
+	procSetTimer.Call(w.hwnd, animTimerID, uintptr(animFrame.Milliseconds()), 0)
+}
+
+// stepAnimation advances the current animation by one frame, destroying the
+// window once an exit animation completes
+func (w *osdWindow) stepAnimation() {
+	hwnd, anim := w.hwnd, w.anim
+	progress := float64(time.Since(anim.start)) / float64(animDuration)
+	progress = min(progress, 1)
+
+	// visible is 0 when fully hidden and 1 when fully in place
+	visible := progress
+	if anim.closing {
+		visible = 1 - progress
+	}
+
+	switch anim.kind {
+	case AnimationFade:
+		procSetLayeredWindowAttributes.Call(hwnd, 0, uintptr(float64(osdAlpha)*visible), LWA_ALPHA)
+	case AnimationSlide:
+		y := anim.y + int32(float64(slideDistance)*(1-visible))
+		procSetWindowPos.Call(hwnd, 0, uintptr(anim.x), uintptr(y), 0, 0, SWP_NOSIZE|SWP_NOZORDER|SWP_NOACTIVATE)
+	}
+
+	if progress >= 1 {
+		procKillTimer.Call(hwnd, animTimerID)
+		if anim.closing {
+			procDestroyWindow.Call(hwnd)
+		}
+	}
+}
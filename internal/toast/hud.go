@@ -0,0 +1,68 @@
+//go:build windows
+
+package toast
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// HUD is a persistent OSD that stays on screen until closed and whose text
+// is updated in place rather than recreating the window
+type HUD struct {
+	window *osdWindow
+	done   chan struct{}
+}
+
+// NewHUD shows a persistent OSD with the given content. The window runs on
+// its own locked OS thread, since Win32 delivers its messages there.
+func NewHUD(title, message string) (*HUD, error) {
+	w := &osdWindow{
+		anim:     animationState{kind: AnimationNone},
+		sticky:   true,
+		position: PositionBottomCenter,
+		colors:   darkColors,
+		scale:    1,
+		maxWidth: osdWidth,
+	}
+	w.setContent(title, message)
+	h := &HUD{window: w, done: make(chan struct{})}
+	created := make(chan error, 1)
+
+	go func() {
+		runtime.LockOSThread()
+		defer close(h.done)
+
+		if err := w.create(); err != nil {
+			created <- err
+			return
+		}
+		created <- nil
+
+		runMessageLoop(w.hwnd, 0)
+	}()
+
+	if err := <-created; err != nil {
+		return nil, fmt.Errorf("OSD notification error: %w", err)
+	}
+	return h, nil
+}
+
+// Update replaces the HUD's text and repaints it
+func (h *HUD) Update(title, message string) {
+	h.window.setContent(title, message)
+	procInvalidateRect.Call(h.window.hwnd, 0, 1)
+}
+
+// SetProgress shows a progress bar along the HUD's bottom edge, filled to
+// fraction (clamped between 0 and 1), for reporting a task's progress
+func (h *HUD) SetProgress(fraction float64) {
+	h.window.setProgress(progressState{kind: progressValue, value: max(0, min(fraction, 1))})
+	h.window.invalidateProgress()
+}
+
+// Close removes the HUD and waits for its window thread to finish
+func (h *HUD) Close() {
+	procPostMessage.Call(h.window.hwnd, WM_CLOSE, 0, 0)
+	<-h.done
+}
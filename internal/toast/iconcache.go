@@ -0,0 +1,191 @@
+//go:build windows
+
+package toast
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/png" // mode icons ship as PNG
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+var (
+	procCreateDIBSection   = gdi32.NewProc("CreateDIBSection")
+	procCreateBitmap       = gdi32.NewProc("CreateBitmap")
+	procCreateIconIndirect = user32.NewProc("CreateIconIndirect")
+	procDestroyIcon        = user32.NewProc("DestroyIcon")
+)
+
+const (
+	BI_RGB         = 0
+	DIB_RGB_COLORS = 0
+)
+
+type BITMAPINFOHEADER struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// ICONINFO describes an icon built by CreateIconIndirect
+type ICONINFO struct {
+	Icon     int32
+	XHotspot uint32
+	YHotspot uint32
+	Mask     uintptr
+	Color    uintptr
+}
+
+// iconKey identifies a rendered icon: the same file can be needed at
+// different sizes, and as a bitmap for the OSD or an HICON for the tray
+type iconKey struct {
+	path  string
+	size  int32
+	hicon bool
+}
+
+// cachedIcon is a rendered icon and the modification time of the file it
+// was decoded from
+type cachedIcon struct {
+	handle  uintptr // HBITMAP or HICON, owned by the cache
+	modTime time.Time
+}
+
+// iconCache keeps rendered icons for the life of the process, so
+// long-running commands (watch, serve, hud, tray) decode each icon once
+// rather than per toast or mode change. Every rendering of a mode comes
+// from its one source image. Entries are invalidated when the file's
+// modification time changes.
+var (
+	iconCacheMu sync.Mutex
+	iconCache   = map[iconKey]cachedIcon{}
+)
+
+// loadIcon returns the icon at path scaled to size x size, as a 32-bit DIB
+// section with premultiplied alpha ready for AlphaBlend. The bitmap belongs
+// to the cache and must not be deleted by the caller.
+func loadIcon(path string, size int32) (uintptr, error) {
+	return loadCached(iconKey{path: path, size: size}, func() (uintptr, error) {
+		return decodeIcon(path, size, true)
+	})
+}
+
+// loadHICON returns the icon at path scaled to size x size as an HICON, for
+// the tray. The icon belongs to the cache and must not be destroyed by the
+// caller.
+func loadHICON(path string, size int32) (uintptr, error) {
+	return loadCached(iconKey{path: path, size: size, hicon: true}, func() (uintptr, error) {
+		// Icons take straight alpha, unlike AlphaBlend
+		bitmap, err := decodeIcon(path, size, false)
+		if err != nil {
+			return 0, err
+		}
+		defer procDeleteObject.Call(bitmap)
+
+		// With a 32-bit color bitmap the mask is ignored, but must exist
+		mask, _, _ := procCreateBitmap.Call(uintptr(size), uintptr(size), 1, 1, 0)
+		defer procDeleteObject.Call(mask)
+
+		info := ICONINFO{Icon: 1, Mask: mask, Color: bitmap}
+		icon, _, err := procCreateIconIndirect.Call(uintptr(unsafe.Pointer(&info)))
+		if icon == 0 {
+			return 0, fmt.Errorf("CreateIconIndirect failed: %s", win32Error(err))
+		}
+		return icon, nil
+	})
+}
+
+// loadCached returns the cached icon for key, rendering it with render when
+// it is missing or its file has changed since
+func loadCached(key iconKey, render func() (uintptr, error)) (uintptr, error) {
+	info, err := os.Stat(key.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read icon: %w", err)
+	}
+
+	iconCacheMu.Lock()
+	defer iconCacheMu.Unlock()
+
+	if cached, ok := iconCache[key]; ok {
+		if cached.modTime.Equal(info.ModTime()) {
+			return cached.handle, nil
+		}
+		if key.hicon {
+			procDestroyIcon.Call(cached.handle)
+		} else {
+			procDeleteObject.Call(cached.handle)
+		}
+		delete(iconCache, key)
+	}
+
+	handle, err := render()
+	if err != nil {
+		return 0, err
+	}
+	iconCache[key] = cachedIcon{handle: handle, modTime: info.ModTime()}
+	return handle, nil
+}
+
+// decodeIcon decodes an image file into a size x size DIB section, scaling
+// with nearest-neighbour sampling. The pixels have premultiplied alpha for
+// AlphaBlend, or straight alpha for building an HICON.
+func decodeIcon(path string, size int32, premultiplied bool) (uintptr, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read icon: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode icon %s: %w", path, err)
+	}
+
+	header := BITMAPINFOHEADER{
+		Size:        uint32(unsafe.Sizeof(BITMAPINFOHEADER{})),
+		Width:       size,
+		Height:      -size, // top-down rows
+		Planes:      1,
+		BitCount:    32,
+		Compression: BI_RGB,
+	}
+	var bits unsafe.Pointer
+	bitmap, _, _ := procCreateDIBSection.Call(0, uintptr(unsafe.Pointer(&header)), DIB_RGB_COLORS, uintptr(unsafe.Pointer(&bits)), 0, 0)
+	if bitmap == 0 || bits == nil {
+		return 0, fmt.Errorf("CreateDIBSection failed")
+	}
+
+	pixels := unsafe.Slice((*byte)(bits), int(size)*int(size)*4)
+	bounds := img.Bounds()
+	for y := range size {
+		for x := range size {
+			sx := bounds.Min.X + int(x)*bounds.Dx()/int(size)
+			sy := bounds.Min.Y + int(y)*bounds.Dy()/int(size)
+			// RGBA returns alpha-premultiplied 16-bit channels
+			r, g, b, a := img.At(sx, sy).RGBA()
+			if !premultiplied {
+				n := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+				r, g, b = uint32(n.R), uint32(n.G), uint32(n.B)
+			}
+			i := (int(y)*int(size) + int(x)) * 4
+			pixels[i+0] = byte(b >> 8)
+			pixels[i+1] = byte(g >> 8)
+			pixels[i+2] = byte(r >> 8)
+			pixels[i+3] = byte(a >> 8)
+		}
+	}
+
+	return bitmap, nil
+}
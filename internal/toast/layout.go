@@ -0,0 +1,66 @@
+//go:build windows
+
+package toast
+
+// osdLayout is the OSD geometry: the window in screen coordinates and the
+// title, message, icon, accent strip and progress bar areas in window
+// coordinates
+type osdLayout struct {
+	Window   RECT
+	Title    RECT
+	Message  RECT
+	Icon     RECT // empty without an icon
+	Accent   RECT
+	Progress RECT
+}
+
+// accentHeight is the height of the mode color strip along the top edge
+const accentHeight = 4
+
+// Width returns the window width
+func (l osdLayout) Width() int32 { return l.Window.Right - l.Window.Left }
+
+// Height returns the window height
+func (l osdLayout) Height() int32 { return l.Window.Bottom - l.Window.Top }
+
+// computeLayout lays out the OSD within a monitor's work area. It only does
+// arithmetic, so it doesn't depend on any window or device context: width
+// is the window width (0 for osdWidth), messageHeight is the height of the
+// word-wrapped message, used when multiline grows the OSD (clamped between
+// osdHeight and osdMaxHeight), and slot is the OSD's place in a stack (0
+// when not stacked). With icon, the mode icon sits on the left, centered
+// vertically, and the text moves right. Every dimension is multiplied by
+// scale; width and messageHeight are expected to be measured at that scale.
+func computeLayout(area RECT, position string, width int32, multiline bool, messageHeight int32, slot int, scale float64, icon bool) osdLayout {
+	s := func(v int32) int32 { return scaleBy(v, scale) }
+	top, padding := s(messageTop), s(messagePadding)
+	if width == 0 {
+		width = s(osdWidth)
+	}
+
+	height := s(osdHeight)
+	if multiline {
+		height = top + messageHeight + padding
+		height = max(s(osdHeight), min(height, s(osdMaxHeight)))
+	}
+
+	textLeft := s(textMargin)
+	var iconRect RECT
+	if icon {
+		size := s(iconSize)
+		iconTop := (height - size) / 2
+		iconRect = RECT{Left: s(iconMargin), Top: iconTop, Right: s(iconMargin) + size, Bottom: iconTop + size}
+		textLeft += s(iconMargin + iconSize)
+	}
+
+	x, y := osdPosition(area, width, height, position)
+	y += stackOffset(slot, height, position)
+	return osdLayout{
+		Window:   RECT{Left: x, Top: y, Right: x + width, Bottom: y + height},
+		Title:    RECT{Left: textLeft, Top: s(15), Right: width - s(textMargin), Bottom: s(45)},
+		Message:  RECT{Left: textLeft, Top: top, Right: width - s(textMargin), Bottom: height - padding},
+		Icon:     iconRect,
+		Accent:   RECT{Right: width, Bottom: s(accentHeight)},
+		Progress: RECT{Top: height - s(progressHeight), Right: width, Bottom: height},
+	}
+}
@@ -0,0 +1,122 @@
+//go:build windows
+
+package toast
+
+import "testing"
+
+// testArea is a 1080p monitor's work area above a 40px taskbar
+var testArea = RECT{Left: 0, Top: 0, Right: 1920, Bottom: 1040}
+
+func TestComputeLayoutDefault(t *testing.T) {
+	l := computeLayout(testArea, PositionBottomCenter, 0, false, 0, 0, 1, false)
+
+	if l.Width() != osdWidth || l.Height() != osdHeight {
+		t.Errorf("size = %dx%d, want %dx%d", l.Width(), l.Height(), osdWidth, osdHeight)
+	}
+	// Centered horizontally
+	if left, right := l.Window.Left-testArea.Left, testArea.Right-l.Window.Right; left != right {
+		t.Errorf("window %+v isn't centered: %d px left, %d px right", l.Window, left, right)
+	}
+	// The top edge sits 15% of the work area up from its bottom
+	if want := testArea.Bottom - int32(float64(testArea.Bottom-testArea.Top)*0.15); l.Window.Top != want {
+		t.Errorf("window top = %d, want %d", l.Window.Top, want)
+	}
+	if l.Icon != (RECT{}) {
+		t.Errorf("icon = %+v without an icon, want empty", l.Icon)
+	}
+	if l.Title.Left != textMargin || l.Message.Right != osdWidth-textMargin {
+		t.Errorf("text areas %+v / %+v don't keep the text margin", l.Title, l.Message)
+	}
+}
+
+func TestComputeLayoutOffsetMonitor(t *testing.T) {
+	// A second monitor to the right of the primary one
+	area := RECT{Left: 1920, Top: 0, Right: 3840, Bottom: 1040}
+	l := computeLayout(area, PositionBottomCenter, 0, false, 0, 0, 1, false)
+
+	if want := area.Left + (1920-osdWidth)/2; l.Window.Left != want {
+		t.Errorf("window left = %d, want %d", l.Window.Left, want)
+	}
+}
+
+func TestComputeLayoutScaled(t *testing.T) {
+	// 200% DPI: every dimension doubles
+	l := computeLayout(testArea, PositionBottomCenter, 0, false, 0, 0, 2, true)
+
+	if l.Width() != 2*osdWidth || l.Height() != 2*osdHeight {
+		t.Errorf("size = %dx%d, want %dx%d", l.Width(), l.Height(), 2*osdWidth, 2*osdHeight)
+	}
+	if want := (testArea.Right - 2*osdWidth) / 2; l.Window.Left != want {
+		t.Errorf("window left = %d, want %d", l.Window.Left, want)
+	}
+	if l.Accent.Bottom != 2*accentHeight || l.Progress.Top != l.Height()-2*progressHeight {
+		t.Errorf("accent %+v / progress %+v aren't scaled", l.Accent, l.Progress)
+	}
+	if l.Icon.Right-l.Icon.Left != 2*iconSize || l.Icon.Left != 2*iconMargin {
+		t.Errorf("icon = %+v, want a %dpx square at %d", l.Icon, 2*iconSize, 2*iconMargin)
+	}
+	// The icon is centered vertically and the text moves right of it
+	if l.Icon.Top != l.Height()-l.Icon.Bottom {
+		t.Errorf("icon %+v isn't centered in a %dpx window", l.Icon, l.Height())
+	}
+	if want := int32(2 * (textMargin + iconMargin + iconSize)); l.Message.Left != want {
+		t.Errorf("message left = %d, want %d", l.Message.Left, want)
+	}
+}
+
+func TestComputeLayoutMultiline(t *testing.T) {
+	tests := []struct {
+		name          string
+		messageHeight int32
+		want          int32
+	}{
+		{"short message keeps the height", 10, osdHeight},
+		{"grows to fit", 120, messageTop + 120 + messagePadding},
+		{"clamped to the maximum", 1000, osdMaxHeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := computeLayout(testArea, PositionBottomCenter, 0, true, tt.messageHeight, 0, 1, false)
+			if l.Height() != tt.want {
+				t.Errorf("height = %d, want %d", l.Height(), tt.want)
+			}
+			if l.Message.Bottom != l.Height()-messagePadding {
+				t.Errorf("message bottom = %d, want %d", l.Message.Bottom, l.Height()-messagePadding)
+			}
+		})
+	}
+}
+
+func TestComputeLayoutClamped(t *testing.T) {
+	// A work area smaller than the OSD, offset from the origin
+	area := RECT{Left: 100, Top: 50, Right: 400, Bottom: 130}
+	for _, position := range []string{PositionBottomCenter, PositionTopLeft, PositionBottomRight, PositionCenter} {
+		l := computeLayout(area, position, 0, false, 0, 0, 1, false)
+		if l.Window.Left != area.Left || l.Window.Top != area.Top {
+			t.Errorf("%s: window %+v not clamped to the work area's top-left", position, l.Window)
+		}
+	}
+
+	// Positions that fit stay inside the work area
+	for _, position := range []string{PositionTopLeft, PositionTopRight, PositionBottomLeft, PositionBottomRight} {
+		l := computeLayout(testArea, position, 0, false, 0, 0, 1.5, true)
+		w := l.Window
+		if w.Left < testArea.Left || w.Top < testArea.Top || w.Right > testArea.Right || w.Bottom > testArea.Bottom {
+			t.Errorf("%s: window %+v leaves the work area %+v", position, w, testArea)
+		}
+	}
+}
+
+func TestComputeLayoutStacked(t *testing.T) {
+	first := computeLayout(testArea, PositionBottomCenter, 0, false, 0, 0, 1, false)
+	second := computeLayout(testArea, PositionBottomCenter, 0, false, 0, 1, 1, false)
+	if want := first.Window.Top - osdHeight - stackGap; second.Window.Top != want {
+		t.Errorf("stacked window top = %d, want %d", second.Window.Top, want)
+	}
+
+	top := computeLayout(testArea, PositionTopCenter, 0, false, 0, 1, 1, false)
+	topFirst := computeLayout(testArea, PositionTopCenter, 0, false, 0, 0, 1, false)
+	if want := topFirst.Window.Top + osdHeight + stackGap; top.Window.Top != want {
+		t.Errorf("stacked top window top = %d, want %d", top.Window.Top, want)
+	}
+}
@@ -0,0 +1,110 @@
+//go:build windows
+
+package toast
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procMonitorFromPoint    = user32.NewProc("MonitorFromPoint")
+	procMonitorFromWindow   = user32.NewProc("MonitorFromWindow")
+	procGetForegroundWindow = user32.NewProc("GetForegroundWindow")
+	procGetMonitorInfo      = user32.NewProc("GetMonitorInfoW")
+	procEnumDisplayMonitors = user32.NewProc("EnumDisplayMonitors")
+)
+
+const (
+	MONITOR_DEFAULTTONULL    = 0x00000000
+	MONITOR_DEFAULTTOPRIMARY = 0x00000001
+	MONITORINFOF_PRIMARY     = 0x00000001
+)
+
+type MONITORINFO struct {
+	CbSize    uint32
+	RcMonitor RECT
+	RcWork    RECT
+	DwFlags   uint32
+}
+
+// Monitor describes one display, as numbered for OSDNotifier.Monitor
+type Monitor struct {
+	Index    int  // 1-based, in the order Windows enumerates displays
+	Bounds   RECT // the whole display, in virtual-screen coordinates
+	WorkArea RECT // the display minus the taskbar
+	Primary  bool
+}
+
+// Monitors lists the attached displays
+func Monitors() ([]Monitor, error) {
+	var monitors []Monitor
+	callback := syscall.NewCallback(func(monitor, hdc, rect, data uintptr) uintptr {
+		info := MONITORINFO{CbSize: uint32(unsafe.Sizeof(MONITORINFO{}))}
+		if ret, _, _ := procGetMonitorInfo.Call(monitor, uintptr(unsafe.Pointer(&info))); ret != 0 {
+			monitors = append(monitors, Monitor{
+				Index:    len(monitors) + 1,
+				Bounds:   info.RcMonitor,
+				WorkArea: info.RcWork,
+				Primary:  info.DwFlags&MONITORINFOF_PRIMARY != 0,
+			})
+		}
+		return 1 // continue enumerating
+	})
+
+	if ret, _, err := procEnumDisplayMonitors.Call(0, 0, callback, 0); ret == 0 {
+		return nil, fmt.Errorf("failed to enumerate monitors: %w", err)
+	}
+	return monitors, nil
+}
+
+// workArea returns the work area (the screen minus the taskbar) of the
+// monitor with the given 1-based index. When it's 0 or no longer attached, that
+// is the monitor showing the foreground window, or the primary monitor if
+// there is none, falling back to the full screen size if it can't be queried.
+func workArea(index int) RECT {
+	if index > 0 {
+		if monitors, err := Monitors(); err == nil && index <= len(monitors) {
+			return monitors[index-1].WorkArea
+		}
+	}
+
+	if area, ok := monitorWorkArea(foregroundMonitor()); ok {
+		return area
+	}
+
+	// The origin is always on the primary monitor
+	monitor, _, _ := procMonitorFromPoint.Call(0, MONITOR_DEFAULTTOPRIMARY)
+	if area, ok := monitorWorkArea(monitor); ok {
+		return area
+	}
+
+	screenWidth, _, _ := procGetSystemMetrics.Call(SM_CXSCREEN)
+	screenHeight, _, _ := procGetSystemMetrics.Call(SM_CYSCREEN)
+	return RECT{Right: int32(screenWidth), Bottom: int32(screenHeight)}
+}
+
+// foregroundMonitor returns the monitor the foreground window is mostly on,
+// or 0 if there's no foreground window (e.g. while the desktop is locked)
+func foregroundMonitor() uintptr {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return 0
+	}
+	monitor, _, _ := procMonitorFromWindow.Call(hwnd, MONITOR_DEFAULTTONULL)
+	return monitor
+}
+
+// monitorWorkArea returns the work area of monitor, or false if it's 0 or
+// can't be queried
+func monitorWorkArea(monitor uintptr) (RECT, bool) {
+	if monitor == 0 {
+		return RECT{}, false
+	}
+	info := MONITORINFO{CbSize: uint32(unsafe.Sizeof(MONITORINFO{}))}
+	if ret, _, _ := procGetMonitorInfo.Call(monitor, uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return RECT{}, false
+	}
+	return info.RcWork, true
+}
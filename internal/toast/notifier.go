@@ -1,7 +1,13 @@
+//go:build windows
+
 package toast
 
 import (
 	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
@@ -39,6 +45,9 @@ var (
 	procKillTimer                  = user32.NewProc("KillTimer")
 	procDestroyWindow              = user32.NewProc("DestroyWindow")
 	procTranslateMessage           = user32.NewProc("TranslateMessage")
+	procInvalidateRect             = user32.NewProc("InvalidateRect")
+	procPostMessage                = user32.NewProc("PostMessageW")
+	procSendMessage                = user32.NewProc("SendMessageW")
 )
 
 const (
@@ -59,9 +68,14 @@ const (
 	WM_PAINT         = 0x000F
 	WM_TIMER         = 0x0113
 	WM_DESTROY       = 0x0002
+	WM_CLOSE         = 0x0010
 	DT_CENTER        = 0x00000001
 	DT_VCENTER       = 0x00000004
 	DT_SINGLELINE    = 0x00000020
+	DT_WORDBREAK     = 0x00000010
+	DT_EDITCONTROL   = 0x00002000
+	DT_CALCRECT      = 0x00000400
+	DT_END_ELLIPSIS  = 0x00008000
 	TRANSPARENT      = 1
 	FW_BOLD          = 700
 	DEFAULT_CHARSET  = 1
@@ -109,122 +123,391 @@ type PAINTSTRUCT struct {
 	RgbReserved [32]byte
 }
 
-// Notifier handles OSD-style overlay notifications
-type Notifier struct {
+const (
+	osdWidth     = 400
+	osdHeight    = 100
+	osdMaxHeight = 300 // multi-line messages are clamped here with an ellipsis
+
+	// longMessageLen is the length above which messages wrap even without Multiline
+	longMessageLen = 45
+)
+
+// OSDNotifier handles OSD-style overlay notifications
+type OSDNotifier struct {
 	appID string
+
+	// Multiline word-wraps the message and grows the OSD to fit it
+	Multiline bool
+
+	// Animation is the entrance/exit style: AnimationNone, AnimationFade or AnimationSlide
+	Animation string
+
+	// Delay postpones showing the OSD, e.g. to avoid clashing with another overlay
+	Delay time.Duration
+
+	// Duration is how long the OSD stays up, between MinDuration and
+	// MaxDuration; 0 means DefaultDuration
+	Duration time.Duration
+
+	// TextShadow draws a dark drop shadow under the text for legibility
+	TextShadow bool
+
+	// Position is where the OSD appears unless a mode overrides it
+	Position string
+
+	// NoTopmost creates the OSD as an ordinary window, so it can't cover
+	// UAC prompts or other dialogs that are already on top
+	NoTopmost bool
+
+	// Stack places the OSD above (or, at the top of the screen, below) other
+	// stacked OSDs still showing, in this or another helper process, instead
+	// of on top of them
+	Stack bool
+
+	// Monitor is the 1-based index (see Monitors) of the display to show the
+	// OSD on; 0, or a monitor that is no longer attached, means the one with
+	// the foreground window (the primary if there is none)
+	Monitor int
+
+	// Scale multiplies the OSD's size and fonts, between MinScale and
+	// MaxScale; 0 means 1
+	Scale float64
+
+	// Theme picks the OSD colors: ThemeDark ("" too), ThemeLight or ThemeAuto
+	Theme string
+
+	// Progress draws a bar along the bottom edge that counts down the time
+	// until the OSD closes
+	Progress bool
+
+	// MaxWidth is how wide, in pixels before scaling, the OSD may grow to
+	// fit long text (up to MaxWidthLimit); 0 or osdWidth keeps it fixed
+	MaxWidth int
 }
 
 // NewNotifier creates a new OSD notifier
-func NewNotifier() *Notifier {
-	return &Notifier{
-		appID: "LenovoLegionToolkit.Helper",
+func NewNotifier() *OSDNotifier {
+	return &OSDNotifier{
+		appID:     "LenovoLegionToolkit.Helper",
+		Animation: AnimationNone,
+		Position:  PositionBottomCenter,
+		MaxWidth:  DefaultMaxWidth,
 	}
 }
 
-var globalMessage string
-var globalTitle string
+// osdWindow is one OSD or HUD window and everything needed to paint it.
+// Each window has its own, looked up by handle in wndProcCallback, so OSDs
+// shown at the same time (a HUD and a toast, or toasts from several
+// goroutines) never draw each other's text or settings.
+type osdWindow struct {
+	hwnd uintptr
+
+	// mu guards title, message and progress, which a HUD updates from
+	// another goroutine while its window thread paints them
+	mu       sync.Mutex
+	title    string
+	message  string
+	progress progressState
+
+	multiline bool
+	layout    osdLayout
+	stackSlot int
+	sticky    bool // persistent HUD: no auto-close, clicks don't dismiss
+	shadow    bool
+	noTopmost bool
+	monitor   int
+	position  string
+	countdown bool // countdown progress bar
+	anim      animationState
+	colors    osdColors
+	scale     float64 // user size factor, see OSDNotifier.Scale
+	icon      uintptr // mode icon (an HBITMAP owned by the icon cache), or 0 for text only
+	accent    uintptr // mode color (COLORREF) for the accent strip, when hasAccent
+	hasAccent bool
+	maxWidth  int32 // at or below osdWidth the OSD keeps its fixed width
+}
+
+// setContent replaces the OSD title and message
+func (w *osdWindow) setContent(title, message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.title = sanitizeText(title)
+	w.message = sanitizeText(message)
+}
+
+// content returns the OSD title and message
+func (w *osdWindow) content() (title, message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.title, w.message
+}
 
-// ShowModeChange displays an OSD overlay notification for power mode change
-func (n *Notifier) ShowModeChange(modeName, iconPath string) error {
-	globalTitle = "Power Mode Changed"
-	globalMessage = fmt.Sprintf("Switched to %s Mode", modeName)
+// scaled applies the OSD's scale to v
+func (w *osdWindow) scaled(v int32) int32 {
+	return scaleBy(v, w.scale)
+}
 
+// ShowModeChange displays an OSD overlay notification for power mode change,
+// with the mode's icon on the left when iconPath loads
+func (n *OSDNotifier) ShowModeChange(message, iconPath, color, position string) error {
 	// Show OSD (blocks for duration, but that's OK - we want the notification to stay)
-	if err := showOSD(globalTitle, globalMessage, 3*time.Second); err != nil {
+	return n.show(ModeChangeTitle, message, iconPath, color, position)
+}
+
+// Show displays an OSD with arbitrary content, e.g. to preview the settings
+func (n *OSDNotifier) Show(title, message string) error {
+	return n.show(title, message, "", "", "")
+}
+
+// ShowIcon displays an OSD with arbitrary content, an icon and an accent
+// color, as ShowModeChange draws them
+func (n *OSDNotifier) ShowIcon(title, message, iconPath, color string) error {
+	return n.show(title, message, iconPath, color, "")
+}
+
+// ShowError displays an error OSD notification
+func (n *OSDNotifier) ShowError(message string) error {
+	return n.show(ErrorTitle, message, "", "", "")
+}
+
+// show sets the OSD content and displays it
+func (n *OSDNotifier) show(title, message, iconPath, color, position string) error {
+	// The mode has already been applied by the time a toast is shown, so
+	// waiting here doesn't hold up the change itself
+	time.Sleep(min(n.Delay, MaxDelay))
+
+	w := &osdWindow{
+		anim:      animationState{kind: n.Animation},
+		shadow:    n.TextShadow,
+		colors:    themeColors(n.Theme),
+		countdown: n.Progress,
+		maxWidth:  int32(min(n.MaxWidth, MaxWidthLimit)),
+		noTopmost: n.NoTopmost,
+		monitor:   n.Monitor,
+		position:  n.Position,
+		scale:     1,
+	}
+	// Error toasts shown by another helper process (e.g. detached) only
+	// carry the title, so it decides the tint
+	if title == ErrorTitle {
+		w.colors = errorColors(w.colors)
+	}
+	w.setContent(title, message)
+	w.multiline = n.Multiline || len([]rune(w.message)) > longMessageLen || strings.Contains(w.message, "\n")
+	if n.Scale > 0 {
+		w.scale = max(MinScale, min(n.Scale, MaxScale))
+	}
+	w.setIcon(iconPath)
+	w.accent, w.hasAccent = parseColor(color)
+	if position != "" {
+		w.position = position
+	}
+	if n.Stack {
+		// With every slot taken the OSD overlaps slot 0; callers cap how
+		// many they show at MaxStack
+		if slot, handle, ok := claimStackSlot(); ok {
+			defer windows.CloseHandle(handle)
+			w.stackSlot = slot
+		}
+	}
+
+	duration := DefaultDuration
+	if n.Duration > 0 {
+		duration = max(MinDuration, min(n.Duration, MaxDuration))
+	}
+	if err := w.show(duration); err != nil {
 		return fmt.Errorf("OSD notification error: %w", err)
 	}
 
 	return nil
 }
 
-// ShowError displays an error OSD notification
-func (n *Notifier) ShowError(message string) error {
-	globalTitle = "Power Mode Error"
-	globalMessage = message
+// sanitizeText strips embedded NULs, which UTF16PtrFromString rejects
+func sanitizeText(s string) string {
+	return strings.ReplaceAll(s, "\x00", "")
+}
 
-	if err := showOSD(globalTitle, globalMessage, 3*time.Second); err != nil {
-		return fmt.Errorf("OSD notification error: %w", err)
+// show displays the OSD and waits until it closes
+func (w *osdWindow) show(duration time.Duration) error {
+	// Win32 delivers a window's messages to the thread that created it
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := w.create(); err != nil {
+		return err
+	}
+
+	// Set timer to close window after duration, leaving room for the exit
+	// animation so the total visible time still matches duration
+	visibleDuration := duration
+	if w.anim.kind != AnimationNone {
+		w.startAnimation(false)
+		visibleDuration = max(duration-animDuration, animDuration)
+	}
+	procSetTimer.Call(w.hwnd, closeTimerID, uintptr(visibleDuration.Milliseconds()), 0)
+	if w.countdown {
+		w.startCountdown(visibleDuration)
 	}
 
+	runMessageLoop(w.hwnd, duration+(2*time.Second)) // Add 2 second buffer
 	return nil
 }
 
-func showOSD(title, message string, duration time.Duration) error {
-	className, _ := syscall.UTF16PtrFromString("LLTHelperOSD")
+// osdClass is the OSD window class, registered once per process (Windows
+// unregisters it when the process exits). Registering it per OSD would also
+// leak a callback each time, and Go only has a limited number of them, so
+// the class and its callback are built once and reused when registering
+// again. Only a successful registration is remembered, so a long-running
+// command recovers from a failed one on its next OSD.
+var osdClass struct {
+	mu         sync.Mutex
+	wc         *WNDCLASSEX
+	registered bool
+}
+
+// registerOSDClass registers the OSD window class unless that already
+// succeeded, or again with reregister (e.g. after CreateWindowEx failed),
+// and returns its name and module
+func registerOSDClass(reregister bool) (*uint16, windows.Handle, error) {
+	osdClass.mu.Lock()
+	defer osdClass.mu.Unlock()
+
+	if osdClass.wc == nil {
+		className, err := syscall.UTF16PtrFromString("LLTHelperOSD")
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid window class name: %w", err)
+		}
+
+		var instance windows.Handle
+		windows.GetModuleHandleEx(0, nil, &instance)
 
-	instance := windows.Handle(0)
-	modhandle, err := syscall.LoadLibrary("kernel32.dll")
-	if err == nil {
-		proc, _ := syscall.GetProcAddress(modhandle, "GetModuleHandleW")
-		if proc != 0 {
-			instance = windows.Handle(proc)
+		osdClass.wc = &WNDCLASSEX{
+			Size:      uint32(unsafe.Sizeof(WNDCLASSEX{})),
+			WndProc:   syscall.NewCallback(wndProcCallback),
+			Instance:  instance,
+			ClassName: className,
 		}
 	}
 
-	wndProc := syscall.NewCallback(wndProcCallback)
+	wc := osdClass.wc
+	if !osdClass.registered || reregister {
+		osdClass.registered = false
+		if err := registerClass(wc); err != nil {
+			return nil, 0, err
+		}
+		osdClass.registered = true
+	}
+	return wc.ClassName, wc.Instance, nil
+}
 
-	wc := WNDCLASSEX{
-		Size:      uint32(unsafe.Sizeof(WNDCLASSEX{})),
-		WndProc:   wndProc,
-		Instance:  instance,
-		ClassName: className,
+// create creates and shows the OSD window for w's content
+func (w *osdWindow) create() error {
+	className, instance, err := registerOSDClass(false)
+	if err != nil {
+		return err
 	}
 
-	ret, _, _ := procRegisterClassEx.Call(uintptr(unsafe.Pointer(&wc)))
-	if ret == 0 {
-		// Class might already be registered, continue anyway
+	// OSD dimensions and position, widening for long text and growing
+	// upwards for wrapped messages
+	title, message := w.content()
+	area := workArea(w.monitor)
+	width := w.fitWidth(area, title, message)
+	var messageHeight int32
+	if w.multiline {
+		messageHeight = w.measureMessageHeight(message, width-2*w.scaled(textMargin)-w.iconSpace())
 	}
+	w.layout = computeLayout(area, w.position, width, w.multiline, messageHeight, w.stackSlot, w.scale, w.icon != 0)
+	osdX, osdY := w.layout.Window.Left, w.layout.Window.Top
 
-	// Get screen dimensions
-	screenWidth, _, _ := procGetSystemMetrics.Call(SM_CXSCREEN)
-	screenHeight, _, _ := procGetSystemMetrics.Call(SM_CYSCREEN)
-
-	// OSD dimensions and position
-	osdWidth := 400
-	osdHeight := 100
-	osdX := int((int(screenWidth) - osdWidth) / 2)
-	osdY := int(screenHeight) - int(float64(screenHeight)*0.15) // 15% from bottom
-
-	windowName, _ := syscall.UTF16PtrFromString("LLT Helper OSD")
-
-	hwnd, _, _ := procCreateWindowEx.Call(
-		WS_EX_LAYERED|WS_EX_TOPMOST|WS_EX_TOOLWINDOW,
-		uintptr(unsafe.Pointer(className)),
-		uintptr(unsafe.Pointer(windowName)),
-		WS_POPUP,
-		uintptr(osdX),
-		uintptr(osdY),
-		uintptr(osdWidth),
-		uintptr(osdHeight),
-		0,
-		0,
-		uintptr(instance),
-		0,
-	)
+	w.anim.x, w.anim.y = osdX, osdY
+	startX, startY, startAlpha := initialPlacement(w.anim.kind, osdX, osdY)
 
+	windowName, err := syscall.UTF16PtrFromString("LLT Helper OSD")
+	if err != nil {
+		return fmt.Errorf("invalid window name: %w", err)
+	}
+
+	exStyle := uintptr(WS_EX_LAYERED | WS_EX_TOPMOST | WS_EX_TOOLWINDOW)
+	if w.noTopmost {
+		exStyle &^= WS_EX_TOPMOST
+	}
+
+	createWindow := func() (uintptr, error) {
+		hwnd, _, err := procCreateWindowEx.Call(
+			exStyle,
+			uintptr(unsafe.Pointer(className)),
+			uintptr(unsafe.Pointer(windowName)),
+			WS_POPUP,
+			uintptr(startX),
+			uintptr(startY),
+			uintptr(w.layout.Width()),
+			uintptr(w.layout.Height()),
+			0,
+			0,
+			uintptr(instance),
+			0,
+		)
+		return hwnd, err
+	}
+
+	hwnd, err := createWindow()
 	if hwnd == 0 {
-		return fmt.Errorf("CreateWindowEx failed")
+		// The class may have been unregistered or never registered; register
+		// it again and retry once before giving up
+		if _, _, regErr := registerOSDClass(true); regErr != nil {
+			return fmt.Errorf("CreateWindowEx failed: %s; %w", win32Error(err), regErr)
+		}
+		if hwnd, err = createWindow(); hwnd == 0 {
+			return fmt.Errorf("CreateWindowEx failed: %s", win32Error(err))
+		}
 	}
+	w.hwnd = hwnd
 
-	// Set window transparency (220 = ~86% opacity)
-	procSetLayeredWindowAttributes.Call(hwnd, 0, 220, LWA_ALPHA)
+	// Tracked before it's shown, so its first WM_PAINT finds its content
+	trackWindow(hwnd, w)
+
+	// Set window transparency (220 = ~86% opacity, or 0 before fading in)
+	procSetLayeredWindowAttributes.Call(hwnd, 0, startAlpha, LWA_ALPHA)
 
 	// Show window
 	procShowWindow.Call(hwnd, SW_SHOW)
 	procUpdateWindow.Call(hwnd)
 
-	// Set timer to close window after duration
-	timerID := uintptr(1)
-	procSetTimer.Call(hwnd, timerID, uintptr(duration.Milliseconds()), 0)
+	return nil
+}
+
+// ERROR_CLASS_ALREADY_EXISTS is returned by RegisterClassEx for a class
+// that is already registered
+const ERROR_CLASS_ALREADY_EXISTS = syscall.Errno(1410)
 
+// registerClass registers a window class. A class left registered earlier
+// in this process is not an error.
+func registerClass(wc *WNDCLASSEX) error {
+	ret, _, err := procRegisterClassEx.Call(uintptr(unsafe.Pointer(wc)))
+	if ret == 0 && err != ERROR_CLASS_ALREADY_EXISTS {
+		return fmt.Errorf("RegisterClassEx failed: %s", win32Error(err))
+	}
+	return nil
+}
+
+// win32Error formats the error from a Win32 call with its numeric code
+func win32Error(err error) string {
+	if errno, ok := err.(syscall.Errno); ok {
+		return fmt.Sprintf("%v (error %d)", errno, uintptr(errno))
+	}
+	return fmt.Sprint(err)
+}
+
+// runMessageLoop pumps window messages until the OSD is destroyed or, when
+// timeout is non-zero, the timeout elapses
+func runMessageLoop(hwnd uintptr, timeout time.Duration) {
 	// Message loop with timeout protection
 	var msg MSG
 	startTime := time.Now()
-	timeoutDuration := duration + (2 * time.Second) // Add 2 second buffer
 
 	for {
 		// Check if we've exceeded timeout
-		if time.Since(startTime) > timeoutDuration {
+		if timeout > 0 && time.Since(startTime) > timeout {
 			procDestroyWindow.Call(hwnd)
 			break
 		}
@@ -238,93 +521,187 @@ func showOSD(title, message string, duration time.Duration) error {
 		if ret == 0 {
 			break
 		}
+		// GetMessage returns a BOOL of -1 on error (e.g. the window handle
+		// became invalid); retrying would spin forever, so give up instead
+		if int32(ret) == -1 {
+			procDestroyWindow.Call(hwnd)
+			break
+		}
 		procTranslateMessage.Call(uintptr(unsafe.Pointer(&msg)))
 		procDispatchMessage.Call(uintptr(unsafe.Pointer(&msg)))
 	}
-
-	return nil
 }
 
-func wndProcCallback(hwnd windows.Handle, msg uint32, wParam, lParam uintptr) uintptr {
-	switch msg {
-	case WM_PAINT:
-		var ps PAINTSTRUCT
-		hdc, _, _ := procBeginPaint.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&ps)))
-
-		// Create dark background
-		bgBrush, _, _ := procCreateSolidBrush.Call(0x00202020) // Dark gray
-		var rect RECT
-		rect.Left = 0
-		rect.Top = 0
-		rect.Right = 400
-		rect.Bottom = 100
-		procFillRect.Call(hdc, uintptr(unsafe.Pointer(&rect)), bgBrush)
-		procDeleteObject.Call(bgBrush)
-
-		// Set text properties
-		procSetBkMode.Call(hdc, TRANSPARENT)
-		procSetTextColor.Call(hdc, 0x00FFFFFF) // White text
-
-		// Create fonts
-		titleFont, _, _ := procCreateFont.Call(
-			24, 0, 0, 0,
-			FW_BOLD,
-			0, 0, 0,
-			DEFAULT_CHARSET,
-			0, 0, 0, 0,
-			uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr("Segoe UI"))),
-		)
-		messageFont, _, _ := procCreateFont.Call(
-			18, 0, 0, 0,
-			0,
-			0, 0, 0,
-			DEFAULT_CHARSET,
-			0, 0, 0, 0,
-			uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr("Segoe UI"))),
-		)
+const (
+	messageTop     = 50 // top of the message area, below the title
+	messagePadding = 15 // space kept below the message
+	shadowOffset   = 2  // text shadow offset in pixels
+)
 
-		// Draw title
-		oldFont, _, _ := procSelectObject.Call(hdc, titleFont)
-		titleRect := RECT{Left: 10, Top: 15, Right: 390, Bottom: 45}
-		titleText, _ := syscall.UTF16PtrFromString(globalTitle)
-		procDrawText.Call(
-			hdc,
-			uintptr(unsafe.Pointer(titleText)),
-			uintptr(^uint(0)), // -1 as uintptr
-			uintptr(unsafe.Pointer(&titleRect)),
-			DT_CENTER|DT_VCENTER|DT_SINGLELINE,
-		)
+// createFont creates a Segoe UI font of the given height and weight
+func createFont(height, weight uintptr) uintptr {
+	fontName, _ := syscall.UTF16PtrFromString("Segoe UI") // constant, cannot contain NUL
+	font, _, _ := procCreateFont.Call(
+		height, 0, 0, 0,
+		weight,
+		0, 0, 0,
+		DEFAULT_CHARSET,
+		0, 0, 0, 0,
+		uintptr(unsafe.Pointer(fontName)),
+	)
+	return font
+}
 
-		// Draw message
-		procSelectObject.Call(hdc, messageFont)
-		messageRect := RECT{Left: 10, Top: 50, Right: 390, Bottom: 85}
-		messageText, _ := syscall.UTF16PtrFromString(globalMessage)
-		procDrawText.Call(
-			hdc,
-			uintptr(unsafe.Pointer(messageText)),
-			uintptr(^uint(0)), // -1 as uintptr
-			uintptr(unsafe.Pointer(&messageRect)),
-			DT_CENTER|DT_VCENTER|DT_SINGLELINE,
-		)
+// measureMessageHeight returns the height of message when word-wrapped to width
+func (w *osdWindow) measureMessageHeight(message string, width int32) int32 {
+	text, err := syscall.UTF16PtrFromString(message)
+	if err != nil {
+		return 0
+	}
+
+	hdc, _, _ := procGetDC.Call(0)
+	if hdc == 0 {
+		return 0
+	}
+	defer procReleaseDC.Call(0, hdc)
 
+	font := createFont(uintptr(w.scaled(18)), 0)
+	oldFont, _, _ := procSelectObject.Call(hdc, font)
+	defer func() {
 		procSelectObject.Call(hdc, oldFont)
-		procDeleteObject.Call(titleFont)
-		procDeleteObject.Call(messageFont)
+		procDeleteObject.Call(font)
+	}()
+
+	rect := RECT{Right: width}
+	procDrawText.Call(
+		hdc,
+		uintptr(unsafe.Pointer(text)),
+		uintptr(^uint(0)), // -1 as uintptr
+		uintptr(unsafe.Pointer(&rect)),
+		DT_CALCRECT|DT_CENTER|DT_WORDBREAK|DT_EDITCONTROL,
+	)
+	return rect.Bottom - rect.Top
+}
+
+// drawText draws text in the theme's color, preceded by a copy in its
+// shadow color offset by shadowOffset when the text shadow is enabled
+func (w *osdWindow) drawText(hdc uintptr, text *uint16, rect RECT, format uintptr) {
+	if w.shadow {
+		offset := w.scaled(shadowOffset)
+		shadowRect := RECT{Left: rect.Left + offset, Top: rect.Top + offset, Right: rect.Right + offset, Bottom: rect.Bottom + offset}
+		procSetTextColor.Call(hdc, w.colors.shadow)
+		procDrawText.Call(hdc, uintptr(unsafe.Pointer(text)), uintptr(^uint(0)), uintptr(unsafe.Pointer(&shadowRect)), format)
+		procSetTextColor.Call(hdc, w.colors.text)
+	}
+
+	procDrawText.Call(
+		hdc,
+		uintptr(unsafe.Pointer(text)),
+		uintptr(^uint(0)), // -1 as uintptr
+		uintptr(unsafe.Pointer(&rect)),
+		format,
+	)
+}
 
-		procEndPaint.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&ps)))
+// paint draws the OSD: background, title, message, icon and progress bar.
+// The GDI objects it creates are released when it returns.
+func (w *osdWindow) paint() {
+	title, message := w.content()
+
+	var ps PAINTSTRUCT
+	hdc, _, _ := procBeginPaint.Call(w.hwnd, uintptr(unsafe.Pointer(&ps)))
+	defer procEndPaint.Call(w.hwnd, uintptr(unsafe.Pointer(&ps)))
+
+	// Fill the background in the theme's color
+	bgBrush, _, _ := procCreateSolidBrush.Call(w.colors.background)
+	rect := RECT{Right: w.layout.Width(), Bottom: w.layout.Height()}
+	procFillRect.Call(hdc, uintptr(unsafe.Pointer(&rect)), bgBrush)
+	procDeleteObject.Call(bgBrush)
+
+	// Set text properties
+	procSetBkMode.Call(hdc, TRANSPARENT)
+	procSetTextColor.Call(hdc, w.colors.text)
+
+	// Create fonts, deleted once the original font is selected back
+	titleFont := createFont(uintptr(w.scaled(24)), FW_BOLD)
+	defer procDeleteObject.Call(titleFont)
+	messageFont := createFont(uintptr(w.scaled(18)), 0)
+	defer procDeleteObject.Call(messageFont)
+
+	// Draw title
+	oldFont, _, _ := procSelectObject.Call(hdc, titleFont)
+	defer procSelectObject.Call(hdc, oldFont)
+	if titleText, err := syscall.UTF16PtrFromString(title); err == nil {
+		w.drawText(hdc, titleText, w.layout.Title, DT_CENTER|DT_VCENTER|DT_SINGLELINE|DT_END_ELLIPSIS)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: OSD title not drawn: %v\n", err)
+	}
+
+	// Draw message
+	procSelectObject.Call(hdc, messageFont)
+	messageFormat := uintptr(DT_CENTER | DT_VCENTER | DT_SINGLELINE | DT_END_ELLIPSIS)
+	if w.multiline {
+		messageFormat = DT_CENTER | DT_WORDBREAK | DT_EDITCONTROL | DT_END_ELLIPSIS
+	}
+	if messageText, err := syscall.UTF16PtrFromString(message); err == nil {
+		w.drawText(hdc, messageText, w.layout.Message, messageFormat)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: OSD message not drawn: %v\n", err)
+	}
+
+	w.paintAccent(hdc)
+	w.paintIcon(hdc)
+	w.paintProgress(hdc)
+}
+
+func wndProcCallback(hwnd windows.Handle, msg uint32, wParam, lParam uintptr) uintptr {
+	// Messages sent while CreateWindowEx runs arrive before the window is
+	// tracked; none of them need the OSD's state
+	w := lookupWindow(uintptr(hwnd))
+	if w == nil {
+		ret, _, _ := procDefWindowProc.Call(uintptr(hwnd), uintptr(msg), wParam, lParam)
+		return ret
+	}
+
+	switch msg {
+	case WM_PAINT:
+		w.paint()
 		return 0
 
 	case WM_TIMER:
-		procKillTimer.Call(uintptr(hwnd), 1)
-		procDestroyWindow.Call(uintptr(hwnd))
+		if wParam == animTimerID {
+			w.stepAnimation()
+			return 0
+		}
+		if wParam == progressTimerID {
+			w.invalidateProgress()
+			return 0
+		}
+		procKillTimer.Call(uintptr(hwnd), closeTimerID)
+		if w.anim.kind == AnimationNone {
+			procDestroyWindow.Call(uintptr(hwnd))
+		} else {
+			w.startAnimation(true)
+		}
 		return 0
 
 	case WM_LBUTTONDOWN:
-		// Close window when clicked
+		// Close window when clicked, unless it's a persistent HUD
+		if !w.sticky {
+			procDestroyWindow.Call(uintptr(hwnd))
+		}
+		return 0
+
+	case WM_CLOSE:
+		// Closed from outside (e.g. Alt+F4 or another process); tear down
+		// directly rather than leaving it to the default proc
+		killTimers(uintptr(hwnd))
 		procDestroyWindow.Call(uintptr(hwnd))
 		return 0
 
 	case WM_DESTROY:
+		killTimers(uintptr(hwnd))
+		untrackWindow(uintptr(hwnd))
 		procPostQuitMessage.Call(0)
 		return 0
 	}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package toast
+
+// The OSD is drawn with Win32; elsewhere the package only builds, for
+// tooling and CI
+
+// OSDNotifier fails every notification with ErrUnsupportedPlatform
+type OSDNotifier struct{}
+
+// NewNotifier creates a notifier that can't show anything
+func NewNotifier() *OSDNotifier {
+	return &OSDNotifier{}
+}
+
+// ShowModeChange fails with ErrUnsupportedPlatform
+func (n *OSDNotifier) ShowModeChange(message, iconPath, color, position string) error {
+	return ErrUnsupportedPlatform
+}
+
+// ShowError fails with ErrUnsupportedPlatform
+func (n *OSDNotifier) ShowError(message string) error {
+	return ErrUnsupportedPlatform
+}
+
+// Show fails with ErrUnsupportedPlatform
+func (n *OSDNotifier) Show(title, message string) error {
+	return ErrUnsupportedPlatform
+}
+
+// ShowIcon fails with ErrUnsupportedPlatform
+func (n *OSDNotifier) ShowIcon(title, message, iconPath, color string) error {
+	return ErrUnsupportedPlatform
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package toast
+
+import "sync"
+
+// openWindows holds the OSD, HUD and tray windows this process has on
+// screen, so wndProcCallback can find each OSD's state by handle and
+// CloseAll can take them down when the helper is stopped. The tray window
+// has no OSD state.
+var (
+	openWindowsMu sync.Mutex
+	openWindows   = make(map[uintptr]*osdWindow)
+)
+
+func trackWindow(hwnd uintptr, w *osdWindow) {
+	openWindowsMu.Lock()
+	defer openWindowsMu.Unlock()
+	openWindows[hwnd] = w
+}
+
+func untrackWindow(hwnd uintptr) {
+	openWindowsMu.Lock()
+	defer openWindowsMu.Unlock()
+	delete(openWindows, hwnd)
+}
+
+// lookupWindow returns the state of the OSD or HUD window hwnd, or nil if
+// it isn't (or is no longer) shown
+func lookupWindow(hwnd uintptr) *osdWindow {
+	openWindowsMu.Lock()
+	defer openWindowsMu.Unlock()
+	return openWindows[hwnd]
+}
+
+// CloseAll closes every OSD and HUD window this process still shows. Each
+// window gets WM_CLOSE on its own thread, which kills its timers and
+// destroys it before CloseAll returns.
+func CloseAll() {
+	openWindowsMu.Lock()
+	hwnds := make([]uintptr, 0, len(openWindows))
+	for hwnd := range openWindows {
+		hwnds = append(hwnds, hwnd)
+	}
+	openWindowsMu.Unlock()
+
+	// Sent without the lock held, since WM_DESTROY untracks the window
+	for _, hwnd := range hwnds {
+		procSendMessage.Call(hwnd, WM_CLOSE, 0, 0)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build windows
+
+package toast
+
+import "golang.org/x/sys/windows"
+
+var (
+	msimg32                = windows.NewLazySystemDLL("msimg32.dll")
+	procAlphaBlend         = msimg32.NewProc("AlphaBlend")
+	procCreateCompatibleDC = gdi32.NewProc("CreateCompatibleDC")
+	procDeleteDC           = gdi32.NewProc("DeleteDC")
+)
+
+const (
+	iconSize   = 64 // the mode icon's side, in pixels before scaling
+	iconMargin = 18 // space left of the icon, centering it in a 100px OSD
+
+	// BLENDFUNCTION for AlphaBlend: AC_SRC_OVER at full opacity, using the
+	// bitmap's per-pixel (premultiplied) alpha
+	blendSourceAlpha = 255<<16 | 1<<24
+)
+
+// setIcon sets the mode icon shown on the OSD's left to the image at path,
+// rendered at the OSD's scale. Without a path, or when it can't be loaded,
+// the OSD falls back to text only.
+func (w *osdWindow) setIcon(path string) {
+	w.icon = 0
+	if path == "" {
+		return
+	}
+	if icon, err := loadIcon(path, w.scaled(iconSize)); err == nil {
+		w.icon = icon
+	}
+}
+
+// iconSpace returns how far the text moves right to make room for the icon
+// at the OSD's scale, or 0 without one
+func (w *osdWindow) iconSpace() int32 {
+	if w.icon == 0 {
+		return 0
+	}
+	return w.scaled(iconMargin + iconSize)
+}
+
+// paintIcon draws the mode icon into the layout's icon area, if there is one
+func (w *osdWindow) paintIcon(hdc uintptr) {
+	if w.icon == 0 {
+		return
+	}
+
+	memDC, _, _ := procCreateCompatibleDC.Call(hdc)
+	if memDC == 0 {
+		return
+	}
+	defer procDeleteDC.Call(memDC)
+	old, _, _ := procSelectObject.Call(memDC, w.icon)
+	defer procSelectObject.Call(memDC, old)
+
+	r := w.layout.Icon
+	size := uintptr(r.Right - r.Left)
+	procAlphaBlend.Call(hdc, uintptr(r.Left), uintptr(r.Top), size, size, memDC, 0, 0, size, size, blendSourceAlpha)
+}
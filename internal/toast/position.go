@@ -0,0 +1,55 @@
+//go:build windows
+
+package toast
+
+// OSD screen positions
+const (
+	PositionTopLeft      = "top-left"
+	PositionTopCenter    = "top-center"
+	PositionTopRight     = "top-right"
+	PositionCenter       = "center"
+	PositionBottomLeft   = "bottom-left"
+	PositionBottomCenter = "bottom-center"
+	PositionBottomRight  = "bottom-right"
+)
+
+// IsValidPosition reports whether name is a supported OSD position
+func IsValidPosition(name string) bool {
+	switch name {
+	case PositionTopLeft, PositionTopCenter, PositionTopRight, PositionCenter,
+		PositionBottomLeft, PositionBottomCenter, PositionBottomRight:
+		return true
+	}
+	return false
+}
+
+// osdPosition places a width x height OSD within the work area. The default
+// bottom-center sits 15% of the work area up from its bottom (taller OSDs
+// growing upwards); other positions keep the same distance from the edges
+// they hug. The result is clamped so the whole window stays inside the work
+// area on small screens.
+func osdPosition(area RECT, width, height int32, position string) (int32, int32) {
+	areaWidth := area.Right - area.Left
+	areaHeight := area.Bottom - area.Top
+	margin := max(int32(float64(areaHeight)*0.15)-osdHeight, 0)
+
+	x := area.Left + (areaWidth-width)/2
+	switch position {
+	case PositionTopLeft, PositionBottomLeft:
+		x = area.Left + margin
+	case PositionTopRight, PositionBottomRight:
+		x = area.Right - margin - width
+	}
+
+	y := area.Bottom - margin - height
+	switch position {
+	case PositionTopLeft, PositionTopCenter, PositionTopRight:
+		y = area.Top + margin
+	case PositionCenter:
+		y = area.Top + (areaHeight-height)/2
+	}
+
+	x = max(area.Left, min(x, area.Right-width))
+	y = max(area.Top, min(y, area.Bottom-height))
+	return x, y
+}
@@ -0,0 +1,89 @@
+//go:build windows
+
+package toast
+
+import (
+	"time"
+	"unsafe"
+)
+
+const (
+	progressTimerID = 3
+	progressFrame   = 50 * time.Millisecond
+	progressHeight  = 4 // height of the bar along the bottom edge, in pixels
+)
+
+// progressKind is what the OSD's progress bar shows, if anything
+type progressKind int
+
+const (
+	progressNone      progressKind = iota
+	progressCountdown              // time left before the OSD closes
+	progressValue                  // a fraction set by the caller (HUD.SetProgress)
+)
+
+// progressState drives the progress bar. It is guarded by osdWindow.mu,
+// since a HUD's progress is set from another goroutine.
+type progressState struct {
+	kind     progressKind
+	start    time.Time
+	duration time.Duration
+	value    float64
+}
+
+// setProgress replaces the progress bar state
+func (w *osdWindow) setProgress(state progressState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.progress = state
+}
+
+// progressFraction returns how full the bar is, from 0 to 1, and whether
+// there is a bar at all
+func (w *osdWindow) progressFraction() (float64, bool) {
+	w.mu.Lock()
+	state := w.progress
+	w.mu.Unlock()
+
+	switch state.kind {
+	case progressCountdown:
+		left := 1 - float64(time.Since(state.start))/float64(state.duration)
+		return max(0, min(left, 1)), true
+	case progressValue:
+		return state.value, true
+	}
+	return 0, false
+}
+
+// startCountdown runs the progress bar down over duration, repainting it on
+// a timer
+func (w *osdWindow) startCountdown(duration time.Duration) {
+	w.setProgress(progressState{kind: progressCountdown, start: time.Now(), duration: duration})
+	procSetTimer.Call(w.hwnd, progressTimerID, uintptr(progressFrame.Milliseconds()), 0)
+}
+
+// invalidateProgress schedules a repaint of just the progress bar, so the
+// text isn't redrawn on every frame
+func (w *osdWindow) invalidateProgress() {
+	rect := w.layout.Progress
+	procInvalidateRect.Call(w.hwnd, uintptr(unsafe.Pointer(&rect)), 0)
+}
+
+// paintProgress draws the progress bar, if any: a track in the background
+// color filled from the left in the theme's accent color
+func (w *osdWindow) paintProgress(hdc uintptr) {
+	fraction, ok := w.progressFraction()
+	if !ok {
+		return
+	}
+
+	bar := w.layout.Progress
+	bar.Right = bar.Left + int32(float64(bar.Right-bar.Left)*fraction)
+	if bar.Right <= bar.Left {
+		return
+	}
+
+	brush, _, _ := procCreateSolidBrush.Call(w.colors.progress)
+	procFillRect.Call(hdc, uintptr(unsafe.Pointer(&bar)), brush)
+	procDeleteObject.Call(brush)
+}
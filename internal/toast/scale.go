@@ -0,0 +1,15 @@
+package toast
+
+import "math"
+
+// Bounds for OSDNotifier.Scale
+const (
+	MinScale = 0.5
+	MaxScale = 3.0
+)
+
+// scaleBy multiplies a pixel or font dimension by scale, rounding to the
+// nearest pixel
+func scaleBy(v int32, scale float64) int32 {
+	return int32(math.Round(float64(v) * scale))
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package toast
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// MaxStack is how many stacked OSDs are shown at once. Callers with more to
+// show should collapse the rest into a "+N more" entry.
+const MaxStack = 4
+
+// stackGap is the space between stacked OSDs
+const stackGap = 10
+
+// stackSlotPrefix names the events that mark stacking slots as taken. A
+// slot is taken for as long as an OSD (in any helper process) holds its
+// event open, so slots free themselves even if a process is killed.
+const stackSlotPrefix = `Local\LLTHelperOSDSlot`
+
+// claimStackSlot takes the lowest free stacking slot, returning the slot and
+// the handle that holds it; closing the handle frees the slot. ok is false
+// when every slot is taken.
+func claimStackSlot() (slot int, handle windows.Handle, ok bool) {
+	for slot := range MaxStack {
+		name, err := windows.UTF16PtrFromString(fmt.Sprintf("%s%d", stackSlotPrefix, slot))
+		if err != nil {
+			return 0, 0, false
+		}
+
+		handle, err := windows.CreateEvent(nil, 1, 0, name)
+		if err == nil {
+			return slot, handle, true
+		}
+		if handle != 0 {
+			windows.CloseHandle(handle)
+		}
+		if !errors.Is(err, windows.ERROR_ALREADY_EXISTS) {
+			return 0, 0, false
+		}
+	}
+	return 0, 0, false
+}
+
+// stackOffset returns how far the OSD in slot moves from its normal place:
+// down for top positions, up for the others, one OSD height plus a gap per
+// slot
+func stackOffset(slot int, height int32, position string) int32 {
+	offset := int32(slot) * (height + stackGap)
+	switch position {
+	case PositionTopLeft, PositionTopCenter, PositionTopRight:
+		return offset
+	}
+	return -offset
+}
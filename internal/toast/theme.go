@@ -0,0 +1,106 @@
+//go:build windows
+
+package toast
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// OSD color themes for OSDNotifier.Theme
+const (
+	ThemeDark  = "dark"  // light text on a dark background (default)
+	ThemeLight = "light" // dark text on a light background
+	ThemeAuto  = "auto"  // follow the Windows app theme
+)
+
+// IsValidTheme reports whether theme is a supported OSD theme
+func IsValidTheme(theme string) bool {
+	switch theme {
+	case ThemeDark, ThemeLight, ThemeAuto:
+		return true
+	}
+	return false
+}
+
+// osdColors are the COLORREF (0x00BBGGRR) values the OSD paints with
+type osdColors struct {
+	background uintptr
+	text       uintptr
+	shadow     uintptr
+	progress   uintptr
+}
+
+var (
+	darkColors  = osdColors{background: 0x00202020, text: 0x00FFFFFF, shadow: 0x00000000, progress: 0x00D77800}
+	lightColors = osdColors{background: 0x00F3F3F3, text: 0x00202020, shadow: 0x00C8C8C8, progress: 0x00D77800}
+
+	// Error OSDs are tinted red so they can't be mistaken for a mode change
+	darkErrorColors  = osdColors{background: 0x001C1C8B, text: 0x00FFFFFF, shadow: 0x00000000, progress: 0x002311E8}
+	lightErrorColors = osdColors{background: 0x00E6E6FD, text: 0x00202020, shadow: 0x00C8C8C8, progress: 0x002311E8}
+)
+
+// themeColors returns the colors for theme. Auto reads the Windows setting
+// each time, so an OSD follows a theme switch without restarting watch.
+func themeColors(theme string) osdColors {
+	switch theme {
+	case ThemeLight:
+		return lightColors
+	case ThemeAuto:
+		if appsUseLightTheme() {
+			return lightColors
+		}
+	}
+	return darkColors
+}
+
+// errorColors returns the red-tinted variant of colors, for error OSDs
+func errorColors(colors osdColors) osdColors {
+	if colors == lightColors {
+		return lightErrorColors
+	}
+	return darkErrorColors
+}
+
+// parseColor turns a "#RRGGBB" color into a COLORREF, reporting false for
+// an empty or malformed one
+func parseColor(color string) (uintptr, bool) {
+	hex, ok := strings.CutPrefix(strings.TrimSpace(color), "#")
+	if !ok || len(hex) != 6 {
+		return 0, false
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	r, g, b := rgb>>16&0xFF, rgb>>8&0xFF, rgb&0xFF
+	return uintptr(b<<16 | g<<8 | r), true
+}
+
+// paintAccent draws the mode's color as a strip along the OSD's top edge,
+// if it has one
+func (w *osdWindow) paintAccent(hdc uintptr) {
+	if !w.hasAccent {
+		return
+	}
+	strip := w.layout.Accent
+	brush, _, _ := procCreateSolidBrush.Call(w.accent)
+	procFillRect.Call(hdc, uintptr(unsafe.Pointer(&strip)), brush)
+	procDeleteObject.Call(brush)
+}
+
+// appsUseLightTheme reports whether Windows is set to the light app theme.
+// If the setting can't be read (older Windows), the OSD stays dark.
+func appsUseLightTheme() bool {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+
+	value, _, err := key.GetIntegerValue("AppsUseLightTheme")
+	return err == nil && value == 1
+}
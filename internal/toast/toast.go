@@ -0,0 +1,60 @@
+package toast
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Notifier reports power mode changes and errors to the user
+type Notifier interface {
+	// message is the full text, e.g. from ModeChangeMessage; position
+	// overrides the notifier's default placement ("" keeps it)
+	ShowModeChange(message, iconPath, color, position string) error
+	ShowError(message string) error
+	// Show displays arbitrary content, for notifications other than mode changes
+	Show(title, message string) error
+}
+
+// NopNotifier is a Notifier that shows nothing, used for --no-toast and in tests
+type NopNotifier struct{}
+
+// ShowModeChange does nothing
+func (NopNotifier) ShowModeChange(message, iconPath, color, position string) error { return nil }
+
+// ShowError does nothing
+func (NopNotifier) ShowError(message string) error { return nil }
+
+// Show does nothing
+func (NopNotifier) Show(title, message string) error { return nil }
+
+// Toast titles
+const (
+	ModeChangeTitle  = "Power Mode Changed"
+	ErrorTitle       = "Power Mode Error"
+	CurrentModeTitle = "Current Power Mode"
+)
+
+// ModeChangeMessage is the default toast message for switching to modeName
+func ModeChangeMessage(modeName string) string {
+	return fmt.Sprintf("Switched to %s Mode", modeName)
+}
+
+// CurrentModeMessage is the toast message for showing, not changing, the mode
+func CurrentModeMessage(modeName string) string {
+	return fmt.Sprintf("%s Mode", modeName)
+}
+
+// MaxDelay caps OSDNotifier.Delay so a typo can't leave the helper hanging
+const MaxDelay = 10 * time.Second
+
+// How long an OSD stays up (OSDNotifier.Duration)
+const (
+	DefaultDuration = 3 * time.Second
+	MinDuration     = 500 * time.Millisecond
+	MaxDuration     = 30 * time.Second
+)
+
+// ErrUnsupportedPlatform is returned by OSDNotifier on platforms other than
+// Windows, where the package only builds
+var ErrUnsupportedPlatform = errors.New("toasts require Windows")
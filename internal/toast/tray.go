@@ -0,0 +1,313 @@
+//go:build windows
+
+package toast
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	shell32                   = windows.NewLazySystemDLL("shell32.dll")
+	procShellNotifyIcon       = shell32.NewProc("Shell_NotifyIconW")
+	procCreatePopupMenu       = user32.NewProc("CreatePopupMenu")
+	procAppendMenu            = user32.NewProc("AppendMenuW")
+	procTrackPopupMenu        = user32.NewProc("TrackPopupMenu")
+	procDestroyMenu           = user32.NewProc("DestroyMenu")
+	procGetCursorPos          = user32.NewProc("GetCursorPos")
+	procSetForegroundWindow   = user32.NewProc("SetForegroundWindow")
+	procRegisterWindowMessage = user32.NewProc("RegisterWindowMessageW")
+	procLoadIcon              = user32.NewProc("LoadIconW")
+)
+
+const (
+	NIM_ADD     = 0x00000000
+	NIM_MODIFY  = 0x00000001
+	NIM_DELETE  = 0x00000002
+	NIF_MESSAGE = 0x00000001
+	NIF_ICON    = 0x00000002
+	NIF_TIP     = 0x00000004
+
+	MF_STRING    = 0x00000000
+	MF_CHECKED   = 0x00000008
+	MF_SEPARATOR = 0x00000800
+
+	TPM_RIGHTBUTTON = 0x0002
+	TPM_NONOTIFY    = 0x0080
+	TPM_RETURNCMD   = 0x0100
+
+	WM_NULL         = 0x0000
+	WM_APP          = 0x8000
+	WM_LBUTTONUP    = 0x0202
+	WM_RBUTTONUP    = 0x0205
+	IDI_APPLICATION = 32512
+	SM_CXSMICON     = 49
+
+	trayCallbackMessage = WM_APP + 1
+	trayIconID          = 1
+	trayExitCommand     = 1 // menu items for modes start after it
+)
+
+// NOTIFYICONDATA is NOTIFYICONDATAW as of Windows Vista
+type NOTIFYICONDATA struct {
+	Size             uint32
+	Wnd              uintptr
+	ID               uint32
+	Flags            uint32
+	CallbackMessage  uint32
+	Icon             uintptr
+	Tip              [128]uint16
+	State            uint32
+	StateMask        uint32
+	Info             [256]uint16
+	TimeoutOrVersion uint32
+	InfoTitle        [64]uint16
+	InfoFlags        uint32
+	GUIDItem         windows.GUID
+	BalloonIcon      uintptr
+}
+
+// TrayItem is an entry of the tray icon's context menu
+type TrayItem struct {
+	ID    string // passed to NewTray's onSelect
+	Label string
+}
+
+// Tray is a notification area icon with a tooltip and a context menu. Like
+// a HUD, its window runs on its own locked OS thread.
+type Tray struct {
+	hwnd     uintptr
+	items    []TrayItem
+	onClick  func()
+	onSelect func(id string)
+	done     chan struct{}
+
+	mu      sync.Mutex
+	tooltip string
+	icon    uintptr // HICON from the icon cache, 0 for the stock icon
+	checked string  // ID of the item shown checked
+}
+
+// activeTray is the tray whose window messages trayWndProc handles; a
+// process shows at most one
+var activeTray *Tray
+
+// taskbarCreated is broadcast when Explorer (re)starts, after which the
+// icon has to be added again
+var taskbarCreated uint32
+
+// NewTray adds an icon to the notification area. Menu items are listed in
+// order, followed by Exit, which closes the tray. onClick runs when the
+// icon is left-clicked and onSelect with the ID of the menu item chosen,
+// each on a goroutine of its own so they may take their time without
+// freezing the icon.
+func NewTray(items []TrayItem, onClick func(), onSelect func(id string)) (*Tray, error) {
+	if activeTray != nil {
+		return nil, fmt.Errorf("a tray icon is already shown")
+	}
+	t := &Tray{items: items, onClick: onClick, onSelect: onSelect, done: make(chan struct{})}
+	activeTray = t
+	created := make(chan error, 1)
+
+	go func() {
+		runtime.LockOSThread()
+		defer close(t.done)
+
+		hwnd, err := createTrayWindow()
+		if err != nil {
+			created <- err
+			return
+		}
+		t.hwnd = hwnd
+		if err := t.notify(NIM_ADD); err != nil {
+			procDestroyWindow.Call(hwnd)
+			created <- err
+			return
+		}
+		trackWindow(hwnd, nil)
+		created <- nil
+
+		runMessageLoop(hwnd, 0)
+	}()
+
+	if err := <-created; err != nil {
+		activeTray = nil
+		return nil, fmt.Errorf("tray icon error: %w", err)
+	}
+	return t, nil
+}
+
+// createTrayWindow creates the hidden window that receives the icon's
+// mouse messages
+func createTrayWindow() (uintptr, error) {
+	className, err := syscall.UTF16PtrFromString("LLTHelperTray")
+	if err != nil {
+		return 0, fmt.Errorf("invalid window class name: %w", err)
+	}
+	wc := WNDCLASSEX{
+		Size:      uint32(unsafe.Sizeof(WNDCLASSEX{})),
+		WndProc:   syscall.NewCallback(trayWndProc),
+		ClassName: className,
+	}
+	if err := registerClass(&wc); err != nil {
+		return 0, err
+	}
+
+	if name, err := syscall.UTF16PtrFromString("TaskbarCreated"); err == nil {
+		msg, _, _ := procRegisterWindowMessage.Call(uintptr(unsafe.Pointer(name)))
+		taskbarCreated = uint32(msg)
+	}
+
+	hwnd, _, err := procCreateWindowEx.Call(WS_EX_TOOLWINDOW, uintptr(unsafe.Pointer(className)), 0, WS_POPUP, 0, 0, 0, 0, 0, 0, 0, 0)
+	if hwnd == 0 {
+		return 0, fmt.Errorf("CreateWindowEx failed: %s", win32Error(err))
+	}
+	return hwnd, nil
+}
+
+// Update changes the tooltip and icon and marks the menu item checked (""
+// for none). An icon that can't be loaded shows the stock application icon.
+func (t *Tray) Update(tooltip, iconPath, checked string) {
+	icon := trayIcon(iconPath)
+
+	t.mu.Lock()
+	t.tooltip, t.icon, t.checked = tooltip, icon, checked
+	t.mu.Unlock()
+
+	t.notify(NIM_MODIFY)
+}
+
+// Done is closed once the tray is gone, whether by Close or its Exit item
+func (t *Tray) Done() <-chan struct{} {
+	return t.done
+}
+
+// Close removes the icon and waits for its window thread to finish
+func (t *Tray) Close() {
+	procPostMessage.Call(t.hwnd, WM_CLOSE, 0, 0)
+	<-t.done
+}
+
+// notify adds, updates or removes the notification area icon
+func (t *Tray) notify(action uintptr) error {
+	data := NOTIFYICONDATA{
+		Wnd:             t.hwnd,
+		ID:              trayIconID,
+		Flags:           NIF_MESSAGE | NIF_ICON | NIF_TIP,
+		CallbackMessage: trayCallbackMessage,
+	}
+	data.Size = uint32(unsafe.Sizeof(data))
+
+	t.mu.Lock()
+	data.Icon = t.icon
+	tip, _ := syscall.UTF16FromString(sanitizeText(t.tooltip))
+	t.mu.Unlock()
+
+	if data.Icon == 0 {
+		data.Icon, _, _ = procLoadIcon.Call(0, IDI_APPLICATION)
+	}
+	copy(data.Tip[:len(data.Tip)-1], tip)
+
+	if ret, _, err := procShellNotifyIcon.Call(action, uintptr(unsafe.Pointer(&data))); ret == 0 && action != NIM_DELETE {
+		return fmt.Errorf("Shell_NotifyIcon failed: %s", win32Error(err))
+	}
+	return nil
+}
+
+// trayIcon returns the mode icon at path, rendered at the small icon size
+// from the same source image as the OSD's, or 0 if it can't be loaded
+func trayIcon(path string) uintptr {
+	if path == "" {
+		return 0
+	}
+	size, _, _ := procGetSystemMetrics.Call(SM_CXSMICON)
+	icon, err := loadHICON(path, int32(size))
+	if err != nil {
+		return 0
+	}
+	return icon
+}
+
+// showMenu shows the context menu at the cursor and returns the chosen
+// command, or 0 if the menu was dismissed
+func (t *Tray) showMenu() uintptr {
+	menu, _, _ := procCreatePopupMenu.Call()
+	if menu == 0 {
+		return 0
+	}
+	defer procDestroyMenu.Call(menu)
+
+	t.mu.Lock()
+	checked := t.checked
+	t.mu.Unlock()
+
+	for i, item := range t.items {
+		flags := uintptr(MF_STRING)
+		if item.ID == checked {
+			flags |= MF_CHECKED
+		}
+		if label, err := syscall.UTF16PtrFromString(sanitizeText(item.Label)); err == nil {
+			procAppendMenu.Call(menu, flags, uintptr(trayExitCommand+1+i), uintptr(unsafe.Pointer(label)))
+		}
+	}
+	procAppendMenu.Call(menu, MF_SEPARATOR, 0, 0)
+	exit, _ := syscall.UTF16PtrFromString("Exit") // constant, cannot contain NUL
+	procAppendMenu.Call(menu, MF_STRING, trayExitCommand, uintptr(unsafe.Pointer(exit)))
+
+	var pt POINT
+	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+
+	// Without this the menu doesn't close when clicking elsewhere
+	procSetForegroundWindow.Call(t.hwnd)
+	cmd, _, _ := procTrackPopupMenu.Call(menu, TPM_RIGHTBUTTON|TPM_NONOTIFY|TPM_RETURNCMD, uintptr(pt.X), uintptr(pt.Y), 0, t.hwnd, 0)
+	procPostMessage.Call(t.hwnd, WM_NULL, 0, 0)
+	return cmd
+}
+
+func trayWndProc(hwnd windows.Handle, msg uint32, wParam, lParam uintptr) uintptr {
+	t := activeTray
+	if t == nil {
+		ret, _, _ := procDefWindowProc.Call(uintptr(hwnd), uintptr(msg), wParam, lParam)
+		return ret
+	}
+
+	switch {
+	case msg == trayCallbackMessage:
+		switch lParam & 0xFFFF {
+		case WM_LBUTTONUP:
+			go t.onClick()
+		case WM_RBUTTONUP:
+			cmd := t.showMenu()
+			switch {
+			case cmd == trayExitCommand:
+				procDestroyWindow.Call(uintptr(hwnd))
+			case cmd > trayExitCommand && int(cmd-trayExitCommand-1) < len(t.items):
+				go t.onSelect(t.items[cmd-trayExitCommand-1].ID)
+			}
+		}
+		return 0
+
+	case msg == taskbarCreated && taskbarCreated != 0:
+		t.notify(NIM_ADD)
+		return 0
+
+	case msg == WM_CLOSE:
+		procDestroyWindow.Call(uintptr(hwnd))
+		return 0
+
+	case msg == WM_DESTROY:
+		t.notify(NIM_DELETE)
+		untrackWindow(uintptr(hwnd))
+		activeTray = nil
+		procPostQuitMessage.Call(0)
+		return 0
+	}
+
+	ret, _, _ := procDefWindowProc.Call(uintptr(hwnd), uintptr(msg), wParam, lParam)
+	return ret
+}
@@ -0,0 +1,70 @@
+//go:build windows
+
+package toast
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Bounds for OSDNotifier.MaxWidth, in pixels before scaling. MinWidth is
+// the OSD's usual width, so a MaxWidth of MinWidth keeps it fixed.
+const (
+	MinWidth        = osdWidth
+	DefaultMaxWidth = 600
+	MaxWidthLimit   = 2000
+	textMargin      = 10 // space kept on each side of the title and message
+)
+
+// fitWidth returns the OSD width, at its scale, that fits the icon, if any,
+// the title and the longest message line, between osdWidth and its maxWidth
+// (and no wider than the work area). Text that still doesn't fit is cut
+// with an ellipsis when drawn.
+func (w *osdWindow) fitWidth(area RECT, title, message string) int32 {
+	width := w.scaled(osdWidth)
+	limit := min(w.scaled(w.maxWidth), area.Right-area.Left)
+	if limit <= width {
+		return width
+	}
+
+	needed := measureTextWidth(title, uintptr(w.scaled(24)), FW_BOLD)
+	for _, line := range strings.Split(message, "\n") {
+		needed = max(needed, measureTextWidth(line, uintptr(w.scaled(18)), 0))
+	}
+	needed += 2*w.scaled(textMargin) + w.iconSpace()
+
+	return max(width, min(needed, limit))
+}
+
+// measureTextWidth returns the width of text drawn on one line in a Segoe
+// UI font of the given height and weight, or 0 if it can't be measured
+func measureTextWidth(s string, height, weight uintptr) int32 {
+	text, err := syscall.UTF16PtrFromString(s)
+	if err != nil || s == "" {
+		return 0
+	}
+
+	hdc, _, _ := procGetDC.Call(0)
+	if hdc == 0 {
+		return 0
+	}
+	defer procReleaseDC.Call(0, hdc)
+
+	font := createFont(height, weight)
+	oldFont, _, _ := procSelectObject.Call(hdc, font)
+	defer func() {
+		procSelectObject.Call(hdc, oldFont)
+		procDeleteObject.Call(font)
+	}()
+
+	var rect RECT
+	procDrawText.Call(
+		hdc,
+		uintptr(unsafe.Pointer(text)),
+		uintptr(^uint(0)), // -1 as uintptr
+		uintptr(unsafe.Pointer(&rect)),
+		DT_CALCRECT|DT_SINGLELINE,
+	)
+	return rect.Right - rect.Left
+}